@@ -26,9 +26,12 @@ type Transaction struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Sender        string                 `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
 	Receiver      string                 `protobuf:"bytes,2,opt,name=receiver,proto3" json:"receiver,omitempty"`
-	Amount        float64                `protobuf:"fixed64,3,opt,name=amount,proto3" json:"amount,omitempty"`
 	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Signature     []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	PublicKey     []byte                 `protobuf:"bytes,6,opt,name=publicKey,proto3" json:"publicKey,omitempty"` // public key của người gửi, dùng để xác minh chữ ký
+	Nonce         uint64                 `protobuf:"varint,7,opt,name=nonce,proto3" json:"nonce,omitempty"`        // nonce của tài khoản gửi, chống gửi lại giao dịch
+	Amount        uint64                 `protobuf:"varint,8,opt,name=amount,proto3" json:"amount,omitempty"`      // số tiền theo đơn vị nhỏ nhất (xem blockchain.Amount)
+	Fee           uint64                 `protobuf:"varint,9,opt,name=fee,proto3" json:"fee,omitempty"`            // phí giao dịch, cùng đơn vị với amount
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -77,56 +80,1309 @@ func (x *Transaction) GetReceiver() string {
 	return ""
 }
 
-func (x *Transaction) GetAmount() float64 {
+func (x *Transaction) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Transaction) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Transaction) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
+
+func (x *Transaction) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *Transaction) GetAmount() uint64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetFee() uint64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+// Header của block, toàn bộ được băm để tạo hash
+type BlockHeader struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Height         uint64                 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Version        uint32                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	PrevHash       string                 `protobuf:"bytes,3,opt,name=prevHash,proto3" json:"prevHash,omitempty"`
+	MerkleRoot     string                 `protobuf:"bytes,4,opt,name=merkleRoot,proto3" json:"merkleRoot,omitempty"`
+	StateRoot      string                 `protobuf:"bytes,5,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
+	Timestamp      int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Proposer       string                 `protobuf:"bytes,7,opt,name=proposer,proto3" json:"proposer,omitempty"`
+	Nonce          uint64                 `protobuf:"varint,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	ChainID        string                 `protobuf:"bytes,9,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Difficulty     uint32                 `protobuf:"varint,10,opt,name=difficulty,proto3" json:"difficulty,omitempty"`        // số bit 0 tối thiểu ở đầu hash (proof-of-work)
+	ValidatorsHash string                 `protobuf:"bytes,11,opt,name=validatorsHash,proto3" json:"validatorsHash,omitempty"` // chỉ có ở genesis block
+	TxCount        uint64                 `protobuf:"varint,12,opt,name=txCount,proto3" json:"txCount,omitempty"`              // số giao dịch của block, bằng leaf_count của Merkle proof
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BlockHeader) Reset() {
+	*x = BlockHeader{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockHeader) ProtoMessage() {}
+
+func (x *BlockHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockHeader.ProtoReflect.Descriptor instead.
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BlockHeader) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetPrevHash() string {
+	if x != nil {
+		return x.PrevHash
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetStateRoot() string {
+	if x != nil {
+		return x.StateRoot
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetProposer() string {
+	if x != nil {
+		return x.Proposer
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetNonce() uint64 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetChainID() string {
+	if x != nil {
+		return x.ChainID
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetDifficulty() uint32 {
+	if x != nil {
+		return x.Difficulty
+	}
+	return 0
+}
+
+func (x *BlockHeader) GetValidatorsHash() string {
+	if x != nil {
+		return x.ValidatorsHash
+	}
+	return ""
+}
+
+func (x *BlockHeader) GetTxCount() uint64 {
+	if x != nil {
+		return x.TxCount
+	}
+	return 0
+}
+
+// Cấu trúc một block
+type Block struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Header        *BlockHeader           `protobuf:"bytes,8,opt,name=header,proto3" json:"header,omitempty"`
+	Transactions  []*Transaction         `protobuf:"bytes,3,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	Hash          string                 `protobuf:"bytes,7,opt,name=hash,proto3" json:"hash,omitempty"`
+	Certificate   *CommitCertificate     `protobuf:"bytes,9,opt,name=certificate,proto3" json:"certificate,omitempty"` // không nằm trong hash, có khi block đã được commit
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Block) GetHeader() *BlockHeader {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *Block) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *Block) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *Block) GetCertificate() *CommitCertificate {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+// Phiếu đồng ý của validator, ký bằng node key trên (height, round, blockHash)
+type Vote struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Height        uint64                 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round         uint64                 `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	BlockHash     string                 `protobuf:"bytes,3,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	ValidatorID   string                 `protobuf:"bytes,4,opt,name=validatorID,proto3" json:"validatorID,omitempty"`
+	Signature     []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Vote) Reset() {
+	*x = Vote{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Vote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Vote) ProtoMessage() {}
+
+func (x *Vote) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Vote.ProtoReflect.Descriptor instead.
+func (*Vote) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Vote) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Vote) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *Vote) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *Vote) GetValidatorID() string {
+	if x != nil {
+		return x.ValidatorID
+	}
+	return ""
+}
+
+func (x *Vote) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+// Tập phiếu chứng minh block được quorum validator đồng ý
+type CommitCertificate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Height        uint64                 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	Round         uint64                 `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	BlockHash     string                 `protobuf:"bytes,3,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Votes         []*Vote                `protobuf:"bytes,4,rep,name=votes,proto3" json:"votes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitCertificate) Reset() {
+	*x = CommitCertificate{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitCertificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitCertificate) ProtoMessage() {}
+
+func (x *CommitCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitCertificate.ProtoReflect.Descriptor instead.
+func (*CommitCertificate) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CommitCertificate) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *CommitCertificate) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *CommitCertificate) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *CommitCertificate) GetVotes() []*Vote {
+	if x != nil {
+		return x.Votes
+	}
+	return nil
+}
+
+// Request gửi proposal từ Leader
+type ProposalRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Block         *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	LeaderID      string                 `protobuf:"bytes,2,opt,name=leaderID,proto3" json:"leaderID,omitempty"`
+	Round         uint64                 `protobuf:"varint,3,opt,name=round,proto3" json:"round,omitempty"` // số lần đề xuất lại ở cùng height
+	Term          uint64                 `protobuf:"varint,4,opt,name=term,proto3" json:"term,omitempty"`   // nhiệm kỳ của leader gửi proposal
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposalRequest) Reset() {
+	*x = ProposalRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposalRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposalRequest) ProtoMessage() {}
+
+func (x *ProposalRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposalRequest.ProtoReflect.Descriptor instead.
+func (*ProposalRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProposalRequest) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *ProposalRequest) GetLeaderID() string {
+	if x != nil {
+		return x.LeaderID
+	}
+	return ""
+}
+
+func (x *ProposalRequest) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *ProposalRequest) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+// Response từ node Follower
+type ProposalResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Accepted      bool                   `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // mã lý do từ chối (blockchain.RejectReason), rỗng nếu chấp nhận
+	Vote          *Vote                  `protobuf:"bytes,4,opt,name=vote,proto3" json:"vote,omitempty"`     // phiếu đã ký, chỉ có khi accepted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProposalResponse) Reset() {
+	*x = ProposalResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProposalResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProposalResponse) ProtoMessage() {}
+
+func (x *ProposalResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProposalResponse.ProtoReflect.Descriptor instead.
+func (*ProposalResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ProposalResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ProposalResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *ProposalResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProposalResponse) GetVote() *Vote {
+	if x != nil {
+		return x.Vote
+	}
+	return nil
+}
+
+// Request và response khi commit block
+type CommitBlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Block         *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	Certificate   *CommitCertificate     `protobuf:"bytes,2,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitBlockRequest) Reset() {
+	*x = CommitBlockRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitBlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitBlockRequest) ProtoMessage() {}
+
+func (x *CommitBlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitBlockRequest.ProtoReflect.Descriptor instead.
+func (*CommitBlockRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CommitBlockRequest) GetBlock() *Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *CommitBlockRequest) GetCertificate() *CommitCertificate {
+	if x != nil {
+		return x.Certificate
+	}
+	return nil
+}
+
+type CommitBlockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // mã lý do từ chối (blockchain.RejectReason), rỗng nếu thành công
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitBlockResponse) Reset() {
+	*x = CommitBlockResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitBlockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitBlockResponse) ProtoMessage() {}
+
+func (x *CommitBlockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitBlockResponse.ProtoReflect.Descriptor instead.
+func (*CommitBlockResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CommitBlockResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CommitBlockResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CommitBlockResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// --- Đồng bộ theo luồng ---
+// Block được gửi theo lô; mỗi lô giới hạn theo số block và kích thước.
+type StreamBlocksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FromHeight    uint64                 `protobuf:"varint,1,opt,name=fromHeight,proto3" json:"fromHeight,omitempty"`
+	ToHeight      uint64                 `protobuf:"varint,2,opt,name=toHeight,proto3" json:"toHeight,omitempty"`       // 0: tới block cuối
+	BatchSize     uint32                 `protobuf:"varint,3,opt,name=batchSize,proto3" json:"batchSize,omitempty"`     // số block tối đa mỗi lô, 0: mặc định của server
+	HeadersOnly   bool                   `protobuf:"varint,4,opt,name=headersOnly,proto3" json:"headersOnly,omitempty"` // chỉ gửi header, hash và certificate (không có giao dịch)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamBlocksRequest) Reset() {
+	*x = StreamBlocksRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamBlocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamBlocksRequest) ProtoMessage() {}
+
+func (x *StreamBlocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamBlocksRequest.ProtoReflect.Descriptor instead.
+func (*StreamBlocksRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StreamBlocksRequest) GetFromHeight() uint64 {
+	if x != nil {
+		return x.FromHeight
+	}
+	return 0
+}
+
+func (x *StreamBlocksRequest) GetToHeight() uint64 {
+	if x != nil {
+		return x.ToHeight
+	}
+	return 0
+}
+
+func (x *StreamBlocksRequest) GetBatchSize() uint32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *StreamBlocksRequest) GetHeadersOnly() bool {
+	if x != nil {
+		return x.HeadersOnly
+	}
+	return false
+}
+
+type BlockBatch struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Blocks        []*Block               `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	TipHeight     uint64                 `protobuf:"varint,2,opt,name=tipHeight,proto3" json:"tipHeight,omitempty"` // height block cuối của server lúc gửi lô
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockBatch) Reset() {
+	*x = BlockBatch{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockBatch) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockBatch) ProtoMessage() {}
+
+func (x *BlockBatch) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockBatch.ProtoReflect.Descriptor instead.
+func (*BlockBatch) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BlockBatch) GetBlocks() []*Block {
+	if x != nil {
+		return x.Blocks
+	}
+	return nil
+}
+
+func (x *BlockBatch) GetTipHeight() uint64 {
+	if x != nil {
+		return x.TipHeight
+	}
+	return 0
+}
+
+// Locator là hash các block của node gọi, từ block cuối lùi về với khoảng cách tăng dần
+type LocatorRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hashes        []string               `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocatorRequest) Reset() {
+	*x = LocatorRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocatorRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocatorRequest) ProtoMessage() {}
+
+func (x *LocatorRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocatorRequest.ProtoReflect.Descriptor instead.
+func (*LocatorRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LocatorRequest) GetHashes() []string {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+type LocatorResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Found         bool                   `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	Height        uint64                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"` // block chung gần nhất trên chuỗi chính của server
+	Hash          string                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`
+	TipHeight     uint64                 `protobuf:"varint,4,opt,name=tipHeight,proto3" json:"tipHeight,omitempty"`
+	TipHash       string                 `protobuf:"bytes,5,opt,name=tipHash,proto3" json:"tipHash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LocatorResponse) Reset() {
+	*x = LocatorResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LocatorResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LocatorResponse) ProtoMessage() {}
+
+func (x *LocatorResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LocatorResponse.ProtoReflect.Descriptor instead.
+func (*LocatorResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LocatorResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *LocatorResponse) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *LocatorResponse) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *LocatorResponse) GetTipHeight() uint64 {
+	if x != nil {
+		return x.TipHeight
+	}
+	return 0
+}
+
+func (x *LocatorResponse) GetTipHash() string {
+	if x != nil {
+		return x.TipHash
+	}
+	return ""
+}
+
+// --- Bầu chọn leader (kiểu Raft) ---
+// Ứng viên xin phiếu cho nhiệm kỳ mới, ký theo blockchain.VoteRequest
+type RequestVoteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Term          uint64                 `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	CandidateID   string                 `protobuf:"bytes,2,opt,name=candidateID,proto3" json:"candidateID,omitempty"`
+	LastHeight    uint64                 `protobuf:"varint,3,opt,name=lastHeight,proto3" json:"lastHeight,omitempty"` // height block cuối của ứng viên
+	LastHash      string                 `protobuf:"bytes,4,opt,name=lastHash,proto3" json:"lastHash,omitempty"`
+	Signature     []byte                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestVoteRequest) Reset() {
+	*x = RequestVoteRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestVoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestVoteRequest) ProtoMessage() {}
+
+func (x *RequestVoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestVoteRequest.ProtoReflect.Descriptor instead.
+func (*RequestVoteRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RequestVoteRequest) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *RequestVoteRequest) GetCandidateID() string {
+	if x != nil {
+		return x.CandidateID
+	}
+	return ""
+}
+
+func (x *RequestVoteRequest) GetLastHeight() uint64 {
+	if x != nil {
+		return x.LastHeight
+	}
+	return 0
+}
+
+func (x *RequestVoteRequest) GetLastHash() string {
+	if x != nil {
+		return x.LastHash
+	}
+	return ""
+}
+
+func (x *RequestVoteRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type RequestVoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Term          uint64                 `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Granted       bool                   `protobuf:"varint,2,opt,name=granted,proto3" json:"granted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestVoteResponse) Reset() {
+	*x = RequestVoteResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestVoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestVoteResponse) ProtoMessage() {}
+
+func (x *RequestVoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestVoteResponse.ProtoReflect.Descriptor instead.
+func (*RequestVoteResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RequestVoteResponse) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *RequestVoteResponse) GetGranted() bool {
+	if x != nil {
+		return x.Granted
+	}
+	return false
+}
+
+// Leader gửi định kỳ để giữ vai trò và báo block cuối của mình, ký theo blockchain.Heartbeat
+type HeartbeatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Term          uint64                 `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	LeaderID      string                 `protobuf:"bytes,2,opt,name=leaderID,proto3" json:"leaderID,omitempty"`
+	Height        uint64                 `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	LastHash      string                 `protobuf:"bytes,4,opt,name=lastHash,proto3" json:"lastHash,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // mili giây Unix
+	Signature     []byte                 `protobuf:"bytes,6,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HeartbeatRequest) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetLeaderID() string {
+	if x != nil {
+		return x.LeaderID
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetLastHash() string {
+	if x != nil {
+		return x.LastHash
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetSignature() []byte {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+type HeartbeatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Term          uint64                 `protobuf:"varint,1,opt,name=term,proto3" json:"term,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HeartbeatResponse) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *HeartbeatResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type TxProofRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxHash        string                 `protobuf:"bytes,1,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxProofRequest) Reset() {
+	*x = TxProofRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxProofRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxProofRequest) ProtoMessage() {}
+
+func (x *TxProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxProofRequest.ProtoReflect.Descriptor instead.
+func (*TxProofRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *TxProofRequest) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+// Merkle proof theo blockchain.MerkleProof: siblings là hash các nút anh em (hex) từ lá đi lên
+type TxProofResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BlockHash     string                 `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Header        *BlockHeader           `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	TxHash        string                 `protobuf:"bytes,3,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	Index         uint32                 `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	LeafCount     uint32                 `protobuf:"varint,5,opt,name=leafCount,proto3" json:"leafCount,omitempty"`
+	Siblings      []string               `protobuf:"bytes,6,rep,name=siblings,proto3" json:"siblings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxProofResponse) Reset() {
+	*x = TxProofResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxProofResponse) ProtoMessage() {}
+
+func (x *TxProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[18]
 	if x != nil {
-		return x.Amount
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxProofResponse.ProtoReflect.Descriptor instead.
+func (*TxProofResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *TxProofResponse) GetBlockHash() string {
+	if x != nil {
+		return x.BlockHash
+	}
+	return ""
+}
+
+func (x *TxProofResponse) GetHeader() *BlockHeader {
+	if x != nil {
+		return x.Header
+	}
+	return nil
+}
+
+func (x *TxProofResponse) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *TxProofResponse) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
 	}
 	return 0
 }
 
-func (x *Transaction) GetTimestamp() int64 {
+func (x *TxProofResponse) GetLeafCount() uint32 {
 	if x != nil {
-		return x.Timestamp
+		return x.LeafCount
 	}
 	return 0
 }
 
-func (x *Transaction) GetSignature() []byte {
+func (x *TxProofResponse) GetSiblings() []string {
 	if x != nil {
-		return x.Signature
+		return x.Siblings
 	}
 	return nil
 }
 
-// Cấu trúc một block
-type Block struct {
+type AccountProofRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Timestamp     int64                  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Transactions  []*Transaction         `protobuf:"bytes,3,rep,name=transactions,proto3" json:"transactions,omitempty"`
-	MerkleRoot    string                 `protobuf:"bytes,4,opt,name=merkleRoot,proto3" json:"merkleRoot,omitempty"`
-	PrevHash      string                 `protobuf:"bytes,5,opt,name=prevHash,proto3" json:"prevHash,omitempty"`
-	Nonce         int32                  `protobuf:"varint,6,opt,name=nonce,proto3" json:"nonce,omitempty"`
-	Hash          string                 `protobuf:"bytes,7,opt,name=hash,proto3" json:"hash,omitempty"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Height        uint64                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Block) Reset() {
-	*x = Block{}
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[1]
+func (x *AccountProofRequest) Reset() {
+	*x = AccountProofRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Block) String() string {
+func (x *AccountProofRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Block) ProtoMessage() {}
+func (*AccountProofRequest) ProtoMessage() {}
 
-func (x *Block) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[1]
+func (x *AccountProofRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -137,84 +1393,159 @@ func (x *Block) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Block.ProtoReflect.Descriptor instead.
-func (*Block) Descriptor() ([]byte, []int) {
-	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use AccountProofRequest.ProtoReflect.Descriptor instead.
+func (*AccountProofRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *Block) GetIndex() int32 {
+func (x *AccountProofRequest) GetAddress() string {
 	if x != nil {
-		return x.Index
+		return x.Address
 	}
-	return 0
+	return ""
 }
 
-func (x *Block) GetTimestamp() int64 {
+func (x *AccountProofRequest) GetHeight() uint64 {
 	if x != nil {
-		return x.Timestamp
+		return x.Height
 	}
 	return 0
 }
 
-func (x *Block) GetTransactions() []*Transaction {
+// State proof theo blockchain.StateProof: siblings là hash các nút anh em (hex) từ gốc đi xuống;
+// exists = false nghĩa là tài khoản chưa tồn tại tại block này
+type AccountProofResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BlockHash      string                 `protobuf:"bytes,1,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	Header         *BlockHeader           `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	Address        string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Exists         bool                   `protobuf:"varint,4,opt,name=exists,proto3" json:"exists,omitempty"`
+	Balance        uint64                 `protobuf:"varint,5,opt,name=balance,proto3" json:"balance,omitempty"`
+	Nonce          uint64                 `protobuf:"varint,6,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Siblings       []string               `protobuf:"bytes,7,rep,name=siblings,proto3" json:"siblings,omitempty"`
+	OtherKey       string                 `protobuf:"bytes,8,opt,name=otherKey,proto3" json:"otherKey,omitempty"`
+	OtherValueHash string                 `protobuf:"bytes,9,opt,name=otherValueHash,proto3" json:"otherValueHash,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AccountProofResponse) Reset() {
+	*x = AccountProofResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountProofResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountProofResponse) ProtoMessage() {}
+
+func (x *AccountProofResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[20]
 	if x != nil {
-		return x.Transactions
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountProofResponse.ProtoReflect.Descriptor instead.
+func (*AccountProofResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *Block) GetMerkleRoot() string {
+func (x *AccountProofResponse) GetBlockHash() string {
 	if x != nil {
-		return x.MerkleRoot
+		return x.BlockHash
 	}
 	return ""
 }
 
-func (x *Block) GetPrevHash() string {
+func (x *AccountProofResponse) GetHeader() *BlockHeader {
 	if x != nil {
-		return x.PrevHash
+		return x.Header
+	}
+	return nil
+}
+
+func (x *AccountProofResponse) GetAddress() string {
+	if x != nil {
+		return x.Address
 	}
 	return ""
 }
 
-func (x *Block) GetNonce() int32 {
+func (x *AccountProofResponse) GetExists() bool {
+	if x != nil {
+		return x.Exists
+	}
+	return false
+}
+
+func (x *AccountProofResponse) GetBalance() uint64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *AccountProofResponse) GetNonce() uint64 {
 	if x != nil {
 		return x.Nonce
 	}
 	return 0
 }
 
-func (x *Block) GetHash() string {
+func (x *AccountProofResponse) GetSiblings() []string {
 	if x != nil {
-		return x.Hash
+		return x.Siblings
+	}
+	return nil
+}
+
+func (x *AccountProofResponse) GetOtherKey() string {
+	if x != nil {
+		return x.OtherKey
 	}
 	return ""
 }
 
-// Request gửi proposal từ Leader
-type ProposalRequest struct {
+func (x *AccountProofResponse) GetOtherValueHash() string {
+	if x != nil {
+		return x.OtherValueHash
+	}
+	return ""
+}
+
+// --- Khám phá peer ---
+type PeerInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Block         *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
-	LeaderID      string                 `protobuf:"bytes,2,opt,name=leaderID,proto3" json:"leaderID,omitempty"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"` // địa chỉ gRPC
+	NodeID        string                 `protobuf:"bytes,2,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProposalRequest) Reset() {
-	*x = ProposalRequest{}
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[2]
+func (x *PeerInfo) Reset() {
+	*x = PeerInfo{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProposalRequest) String() string {
+func (x *PeerInfo) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProposalRequest) ProtoMessage() {}
+func (*PeerInfo) ProtoMessage() {}
 
-func (x *ProposalRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[2]
+func (x *PeerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -225,49 +1556,49 @@ func (x *ProposalRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProposalRequest.ProtoReflect.Descriptor instead.
-func (*ProposalRequest) Descriptor() ([]byte, []int) {
-	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use PeerInfo.ProtoReflect.Descriptor instead.
+func (*PeerInfo) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{21}
 }
 
-func (x *ProposalRequest) GetBlock() *Block {
+func (x *PeerInfo) GetAddress() string {
 	if x != nil {
-		return x.Block
+		return x.Address
 	}
-	return nil
+	return ""
 }
 
-func (x *ProposalRequest) GetLeaderID() string {
+func (x *PeerInfo) GetNodeID() string {
 	if x != nil {
-		return x.LeaderID
+		return x.NodeID
 	}
 	return ""
 }
 
-// Response từ node Follower
-type ProposalResponse struct {
+type PingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	Accepted      bool                   `protobuf:"varint,2,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	NodeID        string                 `protobuf:"bytes,1,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"` // địa chỉ gRPC mà node gọi nhận kết nối
+	Height        uint64                 `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProposalResponse) Reset() {
-	*x = ProposalResponse{}
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[3]
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProposalResponse) String() string {
+func (x *PingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProposalResponse) ProtoMessage() {}
+func (*PingRequest) ProtoMessage() {}
 
-func (x *ProposalResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[3]
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -278,48 +1609,56 @@ func (x *ProposalResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProposalResponse.ProtoReflect.Descriptor instead.
-func (*ProposalResponse) Descriptor() ([]byte, []int) {
-	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *ProposalResponse) GetMessage() string {
+func (x *PingRequest) GetNodeID() string {
 	if x != nil {
-		return x.Message
+		return x.NodeID
 	}
 	return ""
 }
 
-func (x *ProposalResponse) GetAccepted() bool {
+func (x *PingRequest) GetAddress() string {
 	if x != nil {
-		return x.Accepted
+		return x.Address
 	}
-	return false
+	return ""
 }
 
-// Request và response khi commit block
-type CommitBlockRequest struct {
+func (x *PingRequest) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+type PingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Block         *Block                 `protobuf:"bytes,1,opt,name=block,proto3" json:"block,omitempty"`
+	NodeID        string                 `protobuf:"bytes,1,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	Height        uint64                 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	TipHash       string                 `protobuf:"bytes,3,opt,name=tipHash,proto3" json:"tipHash,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CommitBlockRequest) Reset() {
-	*x = CommitBlockRequest{}
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[4]
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CommitBlockRequest) String() string {
+func (x *PingResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommitBlockRequest) ProtoMessage() {}
+func (*PingResponse) ProtoMessage() {}
 
-func (x *CommitBlockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[4]
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -330,41 +1669,55 @@ func (x *CommitBlockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommitBlockRequest.ProtoReflect.Descriptor instead.
-func (*CommitBlockRequest) Descriptor() ([]byte, []int) {
-	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *CommitBlockRequest) GetBlock() *Block {
+func (x *PingResponse) GetNodeID() string {
 	if x != nil {
-		return x.Block
+		return x.NodeID
 	}
-	return nil
+	return ""
 }
 
-type CommitBlockResponse struct {
+func (x *PingResponse) GetHeight() uint64 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *PingResponse) GetTipHash() string {
+	if x != nil {
+		return x.TipHash
+	}
+	return ""
+}
+
+type GetPeersRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	NodeID        string                 `protobuf:"bytes,1,opt,name=nodeID,proto3" json:"nodeID,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CommitBlockResponse) Reset() {
-	*x = CommitBlockResponse{}
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[5]
+func (x *GetPeersRequest) Reset() {
+	*x = GetPeersRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CommitBlockResponse) String() string {
+func (x *GetPeersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CommitBlockResponse) ProtoMessage() {}
+func (*GetPeersRequest) ProtoMessage() {}
 
-func (x *CommitBlockResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[5]
+func (x *GetPeersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -375,48 +1728,47 @@ func (x *CommitBlockResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CommitBlockResponse.ProtoReflect.Descriptor instead.
-func (*CommitBlockResponse) Descriptor() ([]byte, []int) {
-	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use GetPeersRequest.ProtoReflect.Descriptor instead.
+func (*GetPeersRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *CommitBlockResponse) GetMessage() string {
+func (x *GetPeersRequest) GetNodeID() string {
 	if x != nil {
-		return x.Message
+		return x.NodeID
 	}
 	return ""
 }
 
-func (x *CommitBlockResponse) GetSuccess() bool {
+func (x *GetPeersRequest) GetAddress() string {
 	if x != nil {
-		return x.Success
+		return x.Address
 	}
-	return false
+	return ""
 }
 
-// --- Đồng bộ các block bị thiếu ---
-type SyncBlocksRequest struct {
+type GetPeersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	FromHash      string                 `protobuf:"bytes,1,opt,name=fromHash,proto3" json:"fromHash,omitempty"` // Hash của block cuối cùng mà node đang có
+	Peers         []*PeerInfo            `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncBlocksRequest) Reset() {
-	*x = SyncBlocksRequest{}
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[6]
+func (x *GetPeersResponse) Reset() {
+	*x = GetPeersResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncBlocksRequest) String() string {
+func (x *GetPeersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncBlocksRequest) ProtoMessage() {}
+func (*GetPeersResponse) ProtoMessage() {}
 
-func (x *SyncBlocksRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[6]
+func (x *GetPeersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -427,40 +1779,43 @@ func (x *SyncBlocksRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncBlocksRequest.ProtoReflect.Descriptor instead.
-func (*SyncBlocksRequest) Descriptor() ([]byte, []int) {
-	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetPeersResponse.ProtoReflect.Descriptor instead.
+func (*GetPeersResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *SyncBlocksRequest) GetFromHash() string {
+func (x *GetPeersResponse) GetPeers() []*PeerInfo {
 	if x != nil {
-		return x.FromHash
+		return x.Peers
 	}
-	return ""
+	return nil
 }
 
-type SyncBlocksResponse struct {
+// --- Lan truyền giao dịch ---
+type BroadcastTransactionRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Blocks        []*Block               `protobuf:"bytes,1,rep,name=blocks,proto3" json:"blocks,omitempty"`
+	Transaction   *Transaction           `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	NodeID        string                 `protobuf:"bytes,2,opt,name=nodeID,proto3" json:"nodeID,omitempty"`   // node chuyển tiếp giao dịch
+	Address       string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"` // địa chỉ gRPC của node chuyển tiếp, không gửi ngược lại node này
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SyncBlocksResponse) Reset() {
-	*x = SyncBlocksResponse{}
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[7]
+func (x *BroadcastTransactionRequest) Reset() {
+	*x = BroadcastTransactionRequest{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SyncBlocksResponse) String() string {
+func (x *BroadcastTransactionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncBlocksResponse) ProtoMessage() {}
+func (*BroadcastTransactionRequest) ProtoMessage() {}
 
-func (x *SyncBlocksResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[7]
+func (x *BroadcastTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -471,58 +1826,248 @@ func (x *SyncBlocksResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncBlocksResponse.ProtoReflect.Descriptor instead.
-func (*SyncBlocksResponse) Descriptor() ([]byte, []int) {
-	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use BroadcastTransactionRequest.ProtoReflect.Descriptor instead.
+func (*BroadcastTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *SyncBlocksResponse) GetBlocks() []*Block {
+func (x *BroadcastTransactionRequest) GetTransaction() *Transaction {
 	if x != nil {
-		return x.Blocks
+		return x.Transaction
 	}
 	return nil
 }
 
+func (x *BroadcastTransactionRequest) GetNodeID() string {
+	if x != nil {
+		return x.NodeID
+	}
+	return ""
+}
+
+func (x *BroadcastTransactionRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type BroadcastTransactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accepted      bool                   `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"` // false nếu giao dịch đã nhận trước đó hoặc bị mempool từ chối
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BroadcastTransactionResponse) Reset() {
+	*x = BroadcastTransactionResponse{}
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BroadcastTransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BroadcastTransactionResponse) ProtoMessage() {}
+
+func (x *BroadcastTransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_p2p_ProposeBlock_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BroadcastTransactionResponse.ProtoReflect.Descriptor instead.
+func (*BroadcastTransactionResponse) Descriptor() ([]byte, []int) {
+	return file_internal_p2p_ProposeBlock_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *BroadcastTransactionResponse) GetAccepted() bool {
+	if x != nil {
+		return x.Accepted
+	}
+	return false
+}
+
+func (x *BroadcastTransactionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 var File_internal_p2p_ProposeBlock_proto protoreflect.FileDescriptor
 
 const file_internal_p2p_ProposeBlock_proto_rawDesc = "" +
 	"\n" +
-	"\x1finternal/p2p/ProposeBlock.proto\x12\bproposal\"\x95\x01\n" +
+	"\x1finternal/p2p/ProposeBlock.proto\x12\bproposal\"\xe1\x01\n" +
 	"\vTransaction\x12\x16\n" +
 	"\x06sender\x18\x01 \x01(\tR\x06sender\x12\x1a\n" +
-	"\breceiver\x18\x02 \x01(\tR\breceiver\x12\x16\n" +
-	"\x06amount\x18\x03 \x01(\x01R\x06amount\x12\x1c\n" +
+	"\breceiver\x18\x02 \x01(\tR\breceiver\x12\x1c\n" +
 	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\x12\x1c\n" +
-	"\tsignature\x18\x05 \x01(\fR\tsignature\"\xdc\x01\n" +
-	"\x05Block\x12\x14\n" +
-	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x1c\n" +
-	"\ttimestamp\x18\x02 \x01(\x03R\ttimestamp\x129\n" +
-	"\ftransactions\x18\x03 \x03(\v2\x15.proposal.TransactionR\ftransactions\x12\x1e\n" +
+	"\tsignature\x18\x05 \x01(\fR\tsignature\x12\x1c\n" +
+	"\tpublicKey\x18\x06 \x01(\fR\tpublicKey\x12\x14\n" +
+	"\x05nonce\x18\a \x01(\x04R\x05nonce\x12\x16\n" +
+	"\x06amount\x18\b \x01(\x04R\x06amount\x12\x10\n" +
+	"\x03fee\x18\t \x01(\x04R\x03feeJ\x04\b\x03\x10\x04\"\xe5\x02\n" +
+	"\vBlockHeader\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x04R\x06height\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\rR\aversion\x12\x1a\n" +
+	"\bprevHash\x18\x03 \x01(\tR\bprevHash\x12\x1e\n" +
 	"\n" +
 	"merkleRoot\x18\x04 \x01(\tR\n" +
-	"merkleRoot\x12\x1a\n" +
-	"\bprevHash\x18\x05 \x01(\tR\bprevHash\x12\x14\n" +
-	"\x05nonce\x18\x06 \x01(\x05R\x05nonce\x12\x12\n" +
-	"\x04hash\x18\a \x01(\tR\x04hash\"T\n" +
+	"merkleRoot\x12\x1c\n" +
+	"\tstateRoot\x18\x05 \x01(\tR\tstateRoot\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\x12\x1a\n" +
+	"\bproposer\x18\a \x01(\tR\bproposer\x12\x14\n" +
+	"\x05nonce\x18\b \x01(\x04R\x05nonce\x12\x18\n" +
+	"\achainID\x18\t \x01(\tR\achainID\x12\x1e\n" +
+	"\n" +
+	"difficulty\x18\n" +
+	" \x01(\rR\n" +
+	"difficulty\x12&\n" +
+	"\x0evalidatorsHash\x18\v \x01(\tR\x0evalidatorsHash\x12\x18\n" +
+	"\atxCount\x18\f \x01(\x04R\atxCount\"\xe2\x01\n" +
+	"\x05Block\x12-\n" +
+	"\x06header\x18\b \x01(\v2\x15.proposal.BlockHeaderR\x06header\x129\n" +
+	"\ftransactions\x18\x03 \x03(\v2\x15.proposal.TransactionR\ftransactions\x12\x12\n" +
+	"\x04hash\x18\a \x01(\tR\x04hash\x12=\n" +
+	"\vcertificate\x18\t \x01(\v2\x1b.proposal.CommitCertificateR\vcertificateJ\x04\b\x01\x10\x02J\x04\b\x02\x10\x03J\x04\b\x04\x10\x05J\x04\b\x05\x10\x06J\x04\b\x06\x10\a\"\x92\x01\n" +
+	"\x04Vote\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x04R\x06height\x12\x14\n" +
+	"\x05round\x18\x02 \x01(\x04R\x05round\x12\x1c\n" +
+	"\tblockHash\x18\x03 \x01(\tR\tblockHash\x12 \n" +
+	"\vvalidatorID\x18\x04 \x01(\tR\vvalidatorID\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\fR\tsignature\"\x85\x01\n" +
+	"\x11CommitCertificate\x12\x16\n" +
+	"\x06height\x18\x01 \x01(\x04R\x06height\x12\x14\n" +
+	"\x05round\x18\x02 \x01(\x04R\x05round\x12\x1c\n" +
+	"\tblockHash\x18\x03 \x01(\tR\tblockHash\x12$\n" +
+	"\x05votes\x18\x04 \x03(\v2\x0e.proposal.VoteR\x05votes\"~\n" +
 	"\x0fProposalRequest\x12%\n" +
 	"\x05block\x18\x01 \x01(\v2\x0f.proposal.BlockR\x05block\x12\x1a\n" +
-	"\bleaderID\x18\x02 \x01(\tR\bleaderID\"H\n" +
+	"\bleaderID\x18\x02 \x01(\tR\bleaderID\x12\x14\n" +
+	"\x05round\x18\x03 \x01(\x04R\x05round\x12\x12\n" +
+	"\x04term\x18\x04 \x01(\x04R\x04term\"\x84\x01\n" +
 	"\x10ProposalResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1a\n" +
-	"\baccepted\x18\x02 \x01(\bR\baccepted\";\n" +
+	"\baccepted\x18\x02 \x01(\bR\baccepted\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\"\n" +
+	"\x04vote\x18\x04 \x01(\v2\x0e.proposal.VoteR\x04vote\"z\n" +
 	"\x12CommitBlockRequest\x12%\n" +
-	"\x05block\x18\x01 \x01(\v2\x0f.proposal.BlockR\x05block\"I\n" +
+	"\x05block\x18\x01 \x01(\v2\x0f.proposal.BlockR\x05block\x12=\n" +
+	"\vcertificate\x18\x02 \x01(\v2\x1b.proposal.CommitCertificateR\vcertificate\"a\n" +
 	"\x13CommitBlockResponse\x12\x18\n" +
 	"\amessage\x18\x01 \x01(\tR\amessage\x12\x18\n" +
-	"\asuccess\x18\x02 \x01(\bR\asuccess\"/\n" +
-	"\x11SyncBlocksRequest\x12\x1a\n" +
-	"\bfromHash\x18\x01 \x01(\tR\bfromHash\"=\n" +
-	"\x12SyncBlocksResponse\x12'\n" +
-	"\x06blocks\x18\x01 \x03(\v2\x0f.proposal.BlockR\x06blocks2\xf4\x01\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x91\x01\n" +
+	"\x13StreamBlocksRequest\x12\x1e\n" +
+	"\n" +
+	"fromHeight\x18\x01 \x01(\x04R\n" +
+	"fromHeight\x12\x1a\n" +
+	"\btoHeight\x18\x02 \x01(\x04R\btoHeight\x12\x1c\n" +
+	"\tbatchSize\x18\x03 \x01(\rR\tbatchSize\x12 \n" +
+	"\vheadersOnly\x18\x04 \x01(\bR\vheadersOnly\"S\n" +
+	"\n" +
+	"BlockBatch\x12'\n" +
+	"\x06blocks\x18\x01 \x03(\v2\x0f.proposal.BlockR\x06blocks\x12\x1c\n" +
+	"\ttipHeight\x18\x02 \x01(\x04R\ttipHeight\"(\n" +
+	"\x0eLocatorRequest\x12\x16\n" +
+	"\x06hashes\x18\x01 \x03(\tR\x06hashes\"\x8b\x01\n" +
+	"\x0fLocatorResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05found\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x04R\x06height\x12\x12\n" +
+	"\x04hash\x18\x03 \x01(\tR\x04hash\x12\x1c\n" +
+	"\ttipHeight\x18\x04 \x01(\x04R\ttipHeight\x12\x18\n" +
+	"\atipHash\x18\x05 \x01(\tR\atipHash\"\xa4\x01\n" +
+	"\x12RequestVoteRequest\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\x04R\x04term\x12 \n" +
+	"\vcandidateID\x18\x02 \x01(\tR\vcandidateID\x12\x1e\n" +
+	"\n" +
+	"lastHeight\x18\x03 \x01(\x04R\n" +
+	"lastHeight\x12\x1a\n" +
+	"\blastHash\x18\x04 \x01(\tR\blastHash\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\fR\tsignature\"C\n" +
+	"\x13RequestVoteResponse\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\x04R\x04term\x12\x18\n" +
+	"\agranted\x18\x02 \x01(\bR\agranted\"\xb2\x01\n" +
+	"\x10HeartbeatRequest\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\x04R\x04term\x12\x1a\n" +
+	"\bleaderID\x18\x02 \x01(\tR\bleaderID\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x04R\x06height\x12\x1a\n" +
+	"\blastHash\x18\x04 \x01(\tR\blastHash\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12\x1c\n" +
+	"\tsignature\x18\x06 \x01(\fR\tsignature\"A\n" +
+	"\x11HeartbeatResponse\x12\x12\n" +
+	"\x04term\x18\x01 \x01(\x04R\x04term\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\"(\n" +
+	"\x0eTxProofRequest\x12\x16\n" +
+	"\x06txHash\x18\x01 \x01(\tR\x06txHash\"\xc6\x01\n" +
+	"\x0fTxProofResponse\x12\x1c\n" +
+	"\tblockHash\x18\x01 \x01(\tR\tblockHash\x12-\n" +
+	"\x06header\x18\x02 \x01(\v2\x15.proposal.BlockHeaderR\x06header\x12\x16\n" +
+	"\x06txHash\x18\x03 \x01(\tR\x06txHash\x12\x14\n" +
+	"\x05index\x18\x04 \x01(\rR\x05index\x12\x1c\n" +
+	"\tleafCount\x18\x05 \x01(\rR\tleafCount\x12\x1a\n" +
+	"\bsiblings\x18\x06 \x03(\tR\bsiblings\"G\n" +
+	"\x13AccountProofRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x04R\x06height\"\xa5\x02\n" +
+	"\x14AccountProofResponse\x12\x1c\n" +
+	"\tblockHash\x18\x01 \x01(\tR\tblockHash\x12-\n" +
+	"\x06header\x18\x02 \x01(\v2\x15.proposal.BlockHeaderR\x06header\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06exists\x18\x04 \x01(\bR\x06exists\x12\x18\n" +
+	"\abalance\x18\x05 \x01(\x04R\abalance\x12\x14\n" +
+	"\x05nonce\x18\x06 \x01(\x04R\x05nonce\x12\x1a\n" +
+	"\bsiblings\x18\a \x03(\tR\bsiblings\x12\x1a\n" +
+	"\botherKey\x18\b \x01(\tR\botherKey\x12&\n" +
+	"\x0eotherValueHash\x18\t \x01(\tR\x0eotherValueHash\"<\n" +
+	"\bPeerInfo\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06nodeID\x18\x02 \x01(\tR\x06nodeID\"W\n" +
+	"\vPingRequest\x12\x16\n" +
+	"\x06nodeID\x18\x01 \x01(\tR\x06nodeID\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x04R\x06height\"X\n" +
+	"\fPingResponse\x12\x16\n" +
+	"\x06nodeID\x18\x01 \x01(\tR\x06nodeID\x12\x16\n" +
+	"\x06height\x18\x02 \x01(\x04R\x06height\x12\x18\n" +
+	"\atipHash\x18\x03 \x01(\tR\atipHash\"C\n" +
+	"\x0fGetPeersRequest\x12\x16\n" +
+	"\x06nodeID\x18\x01 \x01(\tR\x06nodeID\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"<\n" +
+	"\x10GetPeersResponse\x12(\n" +
+	"\x05peers\x18\x01 \x03(\v2\x12.proposal.PeerInfoR\x05peers\"\x88\x01\n" +
+	"\x1bBroadcastTransactionRequest\x127\n" +
+	"\vtransaction\x18\x01 \x01(\v2\x15.proposal.TransactionR\vtransaction\x12\x16\n" +
+	"\x06nodeID\x18\x02 \x01(\tR\x06nodeID\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\"T\n" +
+	"\x1cBroadcastTransactionResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\bR\baccepted\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage2\xe6\x04\n" +
 	"\x0fProposalService\x12E\n" +
 	"\fSendProposal\x12\x19.proposal.ProposalRequest\x1a\x1a.proposal.ProposalResponse\x12J\n" +
-	"\vCommitBlock\x12\x1c.proposal.CommitBlockRequest\x1a\x1d.proposal.CommitBlockResponse\x12N\n" +
-	"\x11SyncMissingBlocks\x12\x1b.proposal.SyncBlocksRequest\x1a\x1c.proposal.SyncBlocksResponseB\x17Z\x15blockchain/proposalpbb\x06proto3"
+	"\vCommitBlock\x12\x1c.proposal.CommitBlockRequest\x1a\x1d.proposal.CommitBlockResponse\x12I\n" +
+	"\x12FindCommonAncestor\x12\x18.proposal.LocatorRequest\x1a\x19.proposal.LocatorResponse\x12E\n" +
+	"\fStreamBlocks\x12\x1d.proposal.StreamBlocksRequest\x1a\x14.proposal.BlockBatch0\x01\x12J\n" +
+	"\vRequestVote\x12\x1c.proposal.RequestVoteRequest\x1a\x1d.proposal.RequestVoteResponse\x12D\n" +
+	"\tHeartbeat\x12\x1a.proposal.HeartbeatRequest\x1a\x1b.proposal.HeartbeatResponse\x12J\n" +
+	"\x13GetTransactionProof\x12\x18.proposal.TxProofRequest\x1a\x19.proposal.TxProofResponse\x12P\n" +
+	"\x0fGetAccountProof\x12\x1d.proposal.AccountProofRequest\x1a\x1e.proposal.AccountProofResponse2\x85\x01\n" +
+	"\tDiscovery\x125\n" +
+	"\x04Ping\x12\x15.proposal.PingRequest\x1a\x16.proposal.PingResponse\x12A\n" +
+	"\bGetPeers\x12\x19.proposal.GetPeersRequest\x1a\x1a.proposal.GetPeersResponse2o\n" +
+	"\x06Gossip\x12e\n" +
+	"\x14BroadcastTransaction\x12%.proposal.BroadcastTransactionRequest\x1a&.proposal.BroadcastTransactionResponseB\x17Z\x15blockchain/proposalpbb\x06proto3"
 
 var (
 	file_internal_p2p_ProposeBlock_proto_rawDescOnce sync.Once
@@ -536,33 +2081,78 @@ func file_internal_p2p_ProposeBlock_proto_rawDescGZIP() []byte {
 	return file_internal_p2p_ProposeBlock_proto_rawDescData
 }
 
-var file_internal_p2p_ProposeBlock_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_internal_p2p_ProposeBlock_proto_msgTypes = make([]protoimpl.MessageInfo, 28)
 var file_internal_p2p_ProposeBlock_proto_goTypes = []any{
-	(*Transaction)(nil),         // 0: proposal.Transaction
-	(*Block)(nil),               // 1: proposal.Block
-	(*ProposalRequest)(nil),     // 2: proposal.ProposalRequest
-	(*ProposalResponse)(nil),    // 3: proposal.ProposalResponse
-	(*CommitBlockRequest)(nil),  // 4: proposal.CommitBlockRequest
-	(*CommitBlockResponse)(nil), // 5: proposal.CommitBlockResponse
-	(*SyncBlocksRequest)(nil),   // 6: proposal.SyncBlocksRequest
-	(*SyncBlocksResponse)(nil),  // 7: proposal.SyncBlocksResponse
+	(*Transaction)(nil),                  // 0: proposal.Transaction
+	(*BlockHeader)(nil),                  // 1: proposal.BlockHeader
+	(*Block)(nil),                        // 2: proposal.Block
+	(*Vote)(nil),                         // 3: proposal.Vote
+	(*CommitCertificate)(nil),            // 4: proposal.CommitCertificate
+	(*ProposalRequest)(nil),              // 5: proposal.ProposalRequest
+	(*ProposalResponse)(nil),             // 6: proposal.ProposalResponse
+	(*CommitBlockRequest)(nil),           // 7: proposal.CommitBlockRequest
+	(*CommitBlockResponse)(nil),          // 8: proposal.CommitBlockResponse
+	(*StreamBlocksRequest)(nil),          // 9: proposal.StreamBlocksRequest
+	(*BlockBatch)(nil),                   // 10: proposal.BlockBatch
+	(*LocatorRequest)(nil),               // 11: proposal.LocatorRequest
+	(*LocatorResponse)(nil),              // 12: proposal.LocatorResponse
+	(*RequestVoteRequest)(nil),           // 13: proposal.RequestVoteRequest
+	(*RequestVoteResponse)(nil),          // 14: proposal.RequestVoteResponse
+	(*HeartbeatRequest)(nil),             // 15: proposal.HeartbeatRequest
+	(*HeartbeatResponse)(nil),            // 16: proposal.HeartbeatResponse
+	(*TxProofRequest)(nil),               // 17: proposal.TxProofRequest
+	(*TxProofResponse)(nil),              // 18: proposal.TxProofResponse
+	(*AccountProofRequest)(nil),          // 19: proposal.AccountProofRequest
+	(*AccountProofResponse)(nil),         // 20: proposal.AccountProofResponse
+	(*PeerInfo)(nil),                     // 21: proposal.PeerInfo
+	(*PingRequest)(nil),                  // 22: proposal.PingRequest
+	(*PingResponse)(nil),                 // 23: proposal.PingResponse
+	(*GetPeersRequest)(nil),              // 24: proposal.GetPeersRequest
+	(*GetPeersResponse)(nil),             // 25: proposal.GetPeersResponse
+	(*BroadcastTransactionRequest)(nil),  // 26: proposal.BroadcastTransactionRequest
+	(*BroadcastTransactionResponse)(nil), // 27: proposal.BroadcastTransactionResponse
 }
 var file_internal_p2p_ProposeBlock_proto_depIdxs = []int32{
-	0, // 0: proposal.Block.transactions:type_name -> proposal.Transaction
-	1, // 1: proposal.ProposalRequest.block:type_name -> proposal.Block
-	1, // 2: proposal.CommitBlockRequest.block:type_name -> proposal.Block
-	1, // 3: proposal.SyncBlocksResponse.blocks:type_name -> proposal.Block
-	2, // 4: proposal.ProposalService.SendProposal:input_type -> proposal.ProposalRequest
-	4, // 5: proposal.ProposalService.CommitBlock:input_type -> proposal.CommitBlockRequest
-	6, // 6: proposal.ProposalService.SyncMissingBlocks:input_type -> proposal.SyncBlocksRequest
-	3, // 7: proposal.ProposalService.SendProposal:output_type -> proposal.ProposalResponse
-	5, // 8: proposal.ProposalService.CommitBlock:output_type -> proposal.CommitBlockResponse
-	7, // 9: proposal.ProposalService.SyncMissingBlocks:output_type -> proposal.SyncBlocksResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	1,  // 0: proposal.Block.header:type_name -> proposal.BlockHeader
+	0,  // 1: proposal.Block.transactions:type_name -> proposal.Transaction
+	4,  // 2: proposal.Block.certificate:type_name -> proposal.CommitCertificate
+	3,  // 3: proposal.CommitCertificate.votes:type_name -> proposal.Vote
+	2,  // 4: proposal.ProposalRequest.block:type_name -> proposal.Block
+	3,  // 5: proposal.ProposalResponse.vote:type_name -> proposal.Vote
+	2,  // 6: proposal.CommitBlockRequest.block:type_name -> proposal.Block
+	4,  // 7: proposal.CommitBlockRequest.certificate:type_name -> proposal.CommitCertificate
+	2,  // 8: proposal.BlockBatch.blocks:type_name -> proposal.Block
+	1,  // 9: proposal.TxProofResponse.header:type_name -> proposal.BlockHeader
+	1,  // 10: proposal.AccountProofResponse.header:type_name -> proposal.BlockHeader
+	21, // 11: proposal.GetPeersResponse.peers:type_name -> proposal.PeerInfo
+	0,  // 12: proposal.BroadcastTransactionRequest.transaction:type_name -> proposal.Transaction
+	5,  // 13: proposal.ProposalService.SendProposal:input_type -> proposal.ProposalRequest
+	7,  // 14: proposal.ProposalService.CommitBlock:input_type -> proposal.CommitBlockRequest
+	11, // 15: proposal.ProposalService.FindCommonAncestor:input_type -> proposal.LocatorRequest
+	9,  // 16: proposal.ProposalService.StreamBlocks:input_type -> proposal.StreamBlocksRequest
+	13, // 17: proposal.ProposalService.RequestVote:input_type -> proposal.RequestVoteRequest
+	15, // 18: proposal.ProposalService.Heartbeat:input_type -> proposal.HeartbeatRequest
+	17, // 19: proposal.ProposalService.GetTransactionProof:input_type -> proposal.TxProofRequest
+	19, // 20: proposal.ProposalService.GetAccountProof:input_type -> proposal.AccountProofRequest
+	22, // 21: proposal.Discovery.Ping:input_type -> proposal.PingRequest
+	24, // 22: proposal.Discovery.GetPeers:input_type -> proposal.GetPeersRequest
+	26, // 23: proposal.Gossip.BroadcastTransaction:input_type -> proposal.BroadcastTransactionRequest
+	6,  // 24: proposal.ProposalService.SendProposal:output_type -> proposal.ProposalResponse
+	8,  // 25: proposal.ProposalService.CommitBlock:output_type -> proposal.CommitBlockResponse
+	12, // 26: proposal.ProposalService.FindCommonAncestor:output_type -> proposal.LocatorResponse
+	10, // 27: proposal.ProposalService.StreamBlocks:output_type -> proposal.BlockBatch
+	14, // 28: proposal.ProposalService.RequestVote:output_type -> proposal.RequestVoteResponse
+	16, // 29: proposal.ProposalService.Heartbeat:output_type -> proposal.HeartbeatResponse
+	18, // 30: proposal.ProposalService.GetTransactionProof:output_type -> proposal.TxProofResponse
+	20, // 31: proposal.ProposalService.GetAccountProof:output_type -> proposal.AccountProofResponse
+	23, // 32: proposal.Discovery.Ping:output_type -> proposal.PingResponse
+	25, // 33: proposal.Discovery.GetPeers:output_type -> proposal.GetPeersResponse
+	27, // 34: proposal.Gossip.BroadcastTransaction:output_type -> proposal.BroadcastTransactionResponse
+	24, // [24:35] is the sub-list for method output_type
+	13, // [13:24] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_internal_p2p_ProposeBlock_proto_init() }
@@ -576,9 +2166,9 @@ func file_internal_p2p_ProposeBlock_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_internal_p2p_ProposeBlock_proto_rawDesc), len(file_internal_p2p_ProposeBlock_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   28,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_internal_p2p_ProposeBlock_proto_goTypes,
 		DependencyIndexes: file_internal_p2p_ProposeBlock_proto_depIdxs,
@@ -19,9 +19,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ProposalService_SendProposal_FullMethodName      = "/proposal.ProposalService/SendProposal"
-	ProposalService_CommitBlock_FullMethodName       = "/proposal.ProposalService/CommitBlock"
-	ProposalService_SyncMissingBlocks_FullMethodName = "/proposal.ProposalService/SyncMissingBlocks"
+	ProposalService_SendProposal_FullMethodName        = "/proposal.ProposalService/SendProposal"
+	ProposalService_CommitBlock_FullMethodName         = "/proposal.ProposalService/CommitBlock"
+	ProposalService_FindCommonAncestor_FullMethodName  = "/proposal.ProposalService/FindCommonAncestor"
+	ProposalService_StreamBlocks_FullMethodName        = "/proposal.ProposalService/StreamBlocks"
+	ProposalService_RequestVote_FullMethodName         = "/proposal.ProposalService/RequestVote"
+	ProposalService_Heartbeat_FullMethodName           = "/proposal.ProposalService/Heartbeat"
+	ProposalService_GetTransactionProof_FullMethodName = "/proposal.ProposalService/GetTransactionProof"
+	ProposalService_GetAccountProof_FullMethodName     = "/proposal.ProposalService/GetAccountProof"
 )
 
 // ProposalServiceClient is the client API for ProposalService service.
@@ -32,8 +37,15 @@ const (
 type ProposalServiceClient interface {
 	SendProposal(ctx context.Context, in *ProposalRequest, opts ...grpc.CallOption) (*ProposalResponse, error)
 	CommitBlock(ctx context.Context, in *CommitBlockRequest, opts ...grpc.CallOption) (*CommitBlockResponse, error)
-	// Đồng bộ block khi follower bị rớt mạng hoặc restart
-	SyncMissingBlocks(ctx context.Context, in *SyncBlocksRequest, opts ...grpc.CallOption) (*SyncBlocksResponse, error)
+	// Đồng bộ block khi follower bị rớt mạng hoặc restart: tìm block chung bằng locator rồi tải block theo lô
+	FindCommonAncestor(ctx context.Context, in *LocatorRequest, opts ...grpc.CallOption) (*LocatorResponse, error)
+	StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlockBatch], error)
+	// Bầu chọn leader
+	RequestVote(ctx context.Context, in *RequestVoteRequest, opts ...grpc.CallOption) (*RequestVoteResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	// Merkle proof của giao dịch và state proof của tài khoản cho light client
+	GetTransactionProof(ctx context.Context, in *TxProofRequest, opts ...grpc.CallOption) (*TxProofResponse, error)
+	GetAccountProof(ctx context.Context, in *AccountProofRequest, opts ...grpc.CallOption) (*AccountProofResponse, error)
 }
 
 type proposalServiceClient struct {
@@ -64,10 +76,69 @@ func (c *proposalServiceClient) CommitBlock(ctx context.Context, in *CommitBlock
 	return out, nil
 }
 
-func (c *proposalServiceClient) SyncMissingBlocks(ctx context.Context, in *SyncBlocksRequest, opts ...grpc.CallOption) (*SyncBlocksResponse, error) {
+func (c *proposalServiceClient) FindCommonAncestor(ctx context.Context, in *LocatorRequest, opts ...grpc.CallOption) (*LocatorResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(SyncBlocksResponse)
-	err := c.cc.Invoke(ctx, ProposalService_SyncMissingBlocks_FullMethodName, in, out, cOpts...)
+	out := new(LocatorResponse)
+	err := c.cc.Invoke(ctx, ProposalService_FindCommonAncestor_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proposalServiceClient) StreamBlocks(ctx context.Context, in *StreamBlocksRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BlockBatch], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ProposalService_ServiceDesc.Streams[0], ProposalService_StreamBlocks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamBlocksRequest, BlockBatch]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProposalService_StreamBlocksClient = grpc.ServerStreamingClient[BlockBatch]
+
+func (c *proposalServiceClient) RequestVote(ctx context.Context, in *RequestVoteRequest, opts ...grpc.CallOption) (*RequestVoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestVoteResponse)
+	err := c.cc.Invoke(ctx, ProposalService_RequestVote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proposalServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, ProposalService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proposalServiceClient) GetTransactionProof(ctx context.Context, in *TxProofRequest, opts ...grpc.CallOption) (*TxProofResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TxProofResponse)
+	err := c.cc.Invoke(ctx, ProposalService_GetTransactionProof_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *proposalServiceClient) GetAccountProof(ctx context.Context, in *AccountProofRequest, opts ...grpc.CallOption) (*AccountProofResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountProofResponse)
+	err := c.cc.Invoke(ctx, ProposalService_GetAccountProof_FullMethodName, in, out, cOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -82,8 +153,15 @@ func (c *proposalServiceClient) SyncMissingBlocks(ctx context.Context, in *SyncB
 type ProposalServiceServer interface {
 	SendProposal(context.Context, *ProposalRequest) (*ProposalResponse, error)
 	CommitBlock(context.Context, *CommitBlockRequest) (*CommitBlockResponse, error)
-	// Đồng bộ block khi follower bị rớt mạng hoặc restart
-	SyncMissingBlocks(context.Context, *SyncBlocksRequest) (*SyncBlocksResponse, error)
+	// Đồng bộ block khi follower bị rớt mạng hoặc restart: tìm block chung bằng locator rồi tải block theo lô
+	FindCommonAncestor(context.Context, *LocatorRequest) (*LocatorResponse, error)
+	StreamBlocks(*StreamBlocksRequest, grpc.ServerStreamingServer[BlockBatch]) error
+	// Bầu chọn leader
+	RequestVote(context.Context, *RequestVoteRequest) (*RequestVoteResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	// Merkle proof của giao dịch và state proof của tài khoản cho light client
+	GetTransactionProof(context.Context, *TxProofRequest) (*TxProofResponse, error)
+	GetAccountProof(context.Context, *AccountProofRequest) (*AccountProofResponse, error)
 	mustEmbedUnimplementedProposalServiceServer()
 }
 
@@ -100,8 +178,23 @@ func (UnimplementedProposalServiceServer) SendProposal(context.Context, *Proposa
 func (UnimplementedProposalServiceServer) CommitBlock(context.Context, *CommitBlockRequest) (*CommitBlockResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CommitBlock not implemented")
 }
-func (UnimplementedProposalServiceServer) SyncMissingBlocks(context.Context, *SyncBlocksRequest) (*SyncBlocksResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method SyncMissingBlocks not implemented")
+func (UnimplementedProposalServiceServer) FindCommonAncestor(context.Context, *LocatorRequest) (*LocatorResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindCommonAncestor not implemented")
+}
+func (UnimplementedProposalServiceServer) StreamBlocks(*StreamBlocksRequest, grpc.ServerStreamingServer[BlockBatch]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamBlocks not implemented")
+}
+func (UnimplementedProposalServiceServer) RequestVote(context.Context, *RequestVoteRequest) (*RequestVoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestVote not implemented")
+}
+func (UnimplementedProposalServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedProposalServiceServer) GetTransactionProof(context.Context, *TxProofRequest) (*TxProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTransactionProof not implemented")
+}
+func (UnimplementedProposalServiceServer) GetAccountProof(context.Context, *AccountProofRequest) (*AccountProofResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAccountProof not implemented")
 }
 func (UnimplementedProposalServiceServer) mustEmbedUnimplementedProposalServiceServer() {}
 func (UnimplementedProposalServiceServer) testEmbeddedByValue()                         {}
@@ -160,20 +253,103 @@ func _ProposalService_CommitBlock_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
-func _ProposalService_SyncMissingBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SyncBlocksRequest)
+func _ProposalService_FindCommonAncestor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LocatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProposalServiceServer).FindCommonAncestor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProposalService_FindCommonAncestor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProposalServiceServer).FindCommonAncestor(ctx, req.(*LocatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProposalService_StreamBlocks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamBlocksRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProposalServiceServer).StreamBlocks(m, &grpc.GenericServerStream[StreamBlocksRequest, BlockBatch]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ProposalService_StreamBlocksServer = grpc.ServerStreamingServer[BlockBatch]
+
+func _ProposalService_RequestVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestVoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProposalServiceServer).RequestVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProposalService_RequestVote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProposalServiceServer).RequestVote(ctx, req.(*RequestVoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProposalService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProposalServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProposalService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProposalServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProposalService_GetTransactionProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProposalServiceServer).GetTransactionProof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProposalService_GetTransactionProof_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProposalServiceServer).GetTransactionProof(ctx, req.(*TxProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProposalService_GetAccountProof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountProofRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(ProposalServiceServer).SyncMissingBlocks(ctx, in)
+		return srv.(ProposalServiceServer).GetAccountProof(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: ProposalService_SyncMissingBlocks_FullMethodName,
+		FullMethod: ProposalService_GetAccountProof_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ProposalServiceServer).SyncMissingBlocks(ctx, req.(*SyncBlocksRequest))
+		return srv.(ProposalServiceServer).GetAccountProof(ctx, req.(*AccountProofRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -194,8 +370,280 @@ var ProposalService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _ProposalService_CommitBlock_Handler,
 		},
 		{
-			MethodName: "SyncMissingBlocks",
-			Handler:    _ProposalService_SyncMissingBlocks_Handler,
+			MethodName: "FindCommonAncestor",
+			Handler:    _ProposalService_FindCommonAncestor_Handler,
+		},
+		{
+			MethodName: "RequestVote",
+			Handler:    _ProposalService_RequestVote_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _ProposalService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "GetTransactionProof",
+			Handler:    _ProposalService_GetTransactionProof_Handler,
+		},
+		{
+			MethodName: "GetAccountProof",
+			Handler:    _ProposalService_GetAccountProof_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamBlocks",
+			Handler:       _ProposalService_StreamBlocks_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "internal/p2p/ProposeBlock.proto",
+}
+
+const (
+	Discovery_Ping_FullMethodName     = "/proposal.Discovery/Ping"
+	Discovery_GetPeers_FullMethodName = "/proposal.Discovery/GetPeers"
+)
+
+// DiscoveryClient is the client API for Discovery service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Service để các node tìm thấy nhau và kiểm tra peer còn sống
+type DiscoveryClient interface {
+	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
+	GetPeers(ctx context.Context, in *GetPeersRequest, opts ...grpc.CallOption) (*GetPeersResponse, error)
+}
+
+type discoveryClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDiscoveryClient(cc grpc.ClientConnInterface) DiscoveryClient {
+	return &discoveryClient{cc}
+}
+
+func (c *discoveryClient) Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PingResponse)
+	err := c.cc.Invoke(ctx, Discovery_Ping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryClient) GetPeers(ctx context.Context, in *GetPeersRequest, opts ...grpc.CallOption) (*GetPeersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPeersResponse)
+	err := c.cc.Invoke(ctx, Discovery_GetPeers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DiscoveryServer is the server API for Discovery service.
+// All implementations must embed UnimplementedDiscoveryServer
+// for forward compatibility.
+//
+// Service để các node tìm thấy nhau và kiểm tra peer còn sống
+type DiscoveryServer interface {
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	GetPeers(context.Context, *GetPeersRequest) (*GetPeersResponse, error)
+	mustEmbedUnimplementedDiscoveryServer()
+}
+
+// UnimplementedDiscoveryServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedDiscoveryServer struct{}
+
+func (UnimplementedDiscoveryServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedDiscoveryServer) GetPeers(context.Context, *GetPeersRequest) (*GetPeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPeers not implemented")
+}
+func (UnimplementedDiscoveryServer) mustEmbedUnimplementedDiscoveryServer() {}
+func (UnimplementedDiscoveryServer) testEmbeddedByValue()                   {}
+
+// UnsafeDiscoveryServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DiscoveryServer will
+// result in compilation errors.
+type UnsafeDiscoveryServer interface {
+	mustEmbedUnimplementedDiscoveryServer()
+}
+
+func RegisterDiscoveryServer(s grpc.ServiceRegistrar, srv DiscoveryServer) {
+	// If the following call pancis, it indicates UnimplementedDiscoveryServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Discovery_ServiceDesc, srv)
+}
+
+func _Discovery_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Discovery_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Discovery_GetPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServer).GetPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Discovery_GetPeers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServer).GetPeers(ctx, req.(*GetPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Discovery_ServiceDesc is the grpc.ServiceDesc for Discovery service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Discovery_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proposal.Discovery",
+	HandlerType: (*DiscoveryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _Discovery_Ping_Handler,
+		},
+		{
+			MethodName: "GetPeers",
+			Handler:    _Discovery_GetPeers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "internal/p2p/ProposeBlock.proto",
+}
+
+const (
+	Gossip_BroadcastTransaction_FullMethodName = "/proposal.Gossip/BroadcastTransaction"
+)
+
+// GossipClient is the client API for Gossip service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Service để các node chuyển tiếp giao dịch mới cho nhau
+type GossipClient interface {
+	BroadcastTransaction(ctx context.Context, in *BroadcastTransactionRequest, opts ...grpc.CallOption) (*BroadcastTransactionResponse, error)
+}
+
+type gossipClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGossipClient(cc grpc.ClientConnInterface) GossipClient {
+	return &gossipClient{cc}
+}
+
+func (c *gossipClient) BroadcastTransaction(ctx context.Context, in *BroadcastTransactionRequest, opts ...grpc.CallOption) (*BroadcastTransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BroadcastTransactionResponse)
+	err := c.cc.Invoke(ctx, Gossip_BroadcastTransaction_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GossipServer is the server API for Gossip service.
+// All implementations must embed UnimplementedGossipServer
+// for forward compatibility.
+//
+// Service để các node chuyển tiếp giao dịch mới cho nhau
+type GossipServer interface {
+	BroadcastTransaction(context.Context, *BroadcastTransactionRequest) (*BroadcastTransactionResponse, error)
+	mustEmbedUnimplementedGossipServer()
+}
+
+// UnimplementedGossipServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGossipServer struct{}
+
+func (UnimplementedGossipServer) BroadcastTransaction(context.Context, *BroadcastTransactionRequest) (*BroadcastTransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BroadcastTransaction not implemented")
+}
+func (UnimplementedGossipServer) mustEmbedUnimplementedGossipServer() {}
+func (UnimplementedGossipServer) testEmbeddedByValue()                {}
+
+// UnsafeGossipServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GossipServer will
+// result in compilation errors.
+type UnsafeGossipServer interface {
+	mustEmbedUnimplementedGossipServer()
+}
+
+func RegisterGossipServer(s grpc.ServiceRegistrar, srv GossipServer) {
+	// If the following call pancis, it indicates UnimplementedGossipServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Gossip_ServiceDesc, srv)
+}
+
+func _Gossip_BroadcastTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BroadcastTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServer).BroadcastTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gossip_BroadcastTransaction_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServer).BroadcastTransaction(ctx, req.(*BroadcastTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Gossip_ServiceDesc is the grpc.ServiceDesc for Gossip service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Gossip_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proposal.Gossip",
+	HandlerType: (*GossipServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "BroadcastTransaction",
+			Handler:    _Gossip_BroadcastTransaction_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
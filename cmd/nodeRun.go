@@ -1,17 +1,29 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/consensus/pow"
+	"github.com/chauduongphattien/golang-chain/internal/consensus/vote"
+	"github.com/chauduongphattien/golang-chain/internal/election"
 	"github.com/chauduongphattien/golang-chain/internal/handlers"
+	"github.com/chauduongphattien/golang-chain/internal/mempool"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/gossip"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/peers"
+	"github.com/chauduongphattien/golang-chain/internal/syncer"
 	"github.com/chauduongphattien/golang-chain/pkg/storage"
 	"google.golang.org/grpc"
 
 	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
 	"github.com/chauduongphattien/golang-chain/internal/p2p/service"
 )
 
@@ -25,24 +37,141 @@ func main() {
 		tcpPort = "50050"
 	}
 
+	if raw := os.Getenv("AMOUNT_DECIMALS"); raw != "" {
+		decimals, err := strconv.ParseUint(raw, 10, 8)
+		if err != nil {
+			log.Fatalf("AMOUNT_DECIMALS không hợp lệ: %v", err)
+		}
+		if err := blockchain.SetAmountDecimals(uint8(decimals)); err != nil {
+			log.Fatalf("AMOUNT_DECIMALS không hợp lệ: %v", err)
+		}
+	}
+
+	genesisFile := os.Getenv("GENESIS_FILE")
+	if genesisFile == "" {
+		genesisFile = "./genesis.json"
+	}
+	genesis, err := blockchain.LoadGenesis(genesisFile)
+	if err != nil {
+		log.Fatalf("Không đọc được genesis: %v", err)
+	}
+	genesisBlock := genesis.Block()
+
 	db := storage.NewStorage("./pkg/storage/data")
 	defer db.Close()
 
-	leaderHandler := handlers.NewLeaderHandler(db)
+	if err := db.InitGenesis(genesisBlock); err != nil {
+		log.Fatalf("Khởi tạo genesis thất bại: %v", err)
+	}
+	validatorSets, err := consensus.NewValidatorSetsFromGenesis(genesis)
+	if err != nil {
+		log.Fatalf("Tập validator genesis không hợp lệ: %v", err)
+	}
+	if err := db.InitValidatorSets(validatorSets); err != nil {
+		log.Fatalf("Lưu tập validator thất bại: %v", err)
+	}
+	grpcclient.SetGenesisHash(genesisBlock.Hash)
+	log.Printf("Chain %s, genesis %s", genesis.ChainID, genesisBlock.Hash)
+
+	nodeID := os.Getenv("NODE_ID")
+	if nodeID == "" {
+		nodeID = "leader-1"
+	}
+	signer, err := consensus.NewSigner(nodeID, os.Getenv("NODE_KEY"))
+	if err != nil {
+		log.Fatalf("NODE_KEY không hợp lệ: %v", err)
+	}
+	for _, set := range validatorSets {
+		if v, ok := set.Get(nodeID); ok && v.PublicKey != signer.PublicKey() {
+			log.Printf("Cảnh báo: NODE_KEY không khớp public key của validator %s từ height %d, phiếu của node sẽ bị từ chối", nodeID, set.Height)
+		}
+	}
+
+	engineName, err := consensus.EngineFromEnv()
+	if err != nil {
+		log.Fatalf("CONSENSUS không hợp lệ: %v", err)
+	}
+	var (
+		engine consensus.Engine
+		elect  *election.Election
+	)
+	switch engineName {
+	case "pow":
+		engine = pow.New(db, pow.LoadConfig(), nodeID)
+	default:
+		elect, err = election.New(signer, db, election.LoadConfig())
+		if err != nil {
+			log.Fatalf("Khởi tạo bầu chọn leader thất bại: %v", err)
+		}
+		go elect.Run(context.Background())
+		engine = vote.New(db, signer, elect)
+	}
+	log.Printf("Thuật toán đồng thuận: %s", engine.Name())
+
+	// Seed là các validator trong genesis (mọi tập) cùng SEEDS; P2P_ADDRESS là địa chỉ gRPC quảng bá cho peer khác
+	selfAddr := os.Getenv("P2P_ADDRESS")
+	var seeds []string
+	seen := make(map[string]bool)
+	for _, set := range validatorSets {
+		if v, ok := set.Get(nodeID); ok && selfAddr == "" {
+			selfAddr = v.Address
+		}
+		for _, v := range set.Peers(nodeID) {
+			if !seen[v.Address] {
+				seen[v.Address] = true
+				seeds = append(seeds, v.Address)
+			}
+		}
+	}
+	if selfAddr == "" {
+		selfAddr = "localhost:" + tcpPort
+	}
+	seeds = append(seeds, peers.ParseSeeds(os.Getenv("SEEDS"))...)
+	peerManager, err := peers.New(nodeID, selfAddr, seeds, db, db, peers.LoadConfig())
+	if err != nil {
+		log.Fatalf("Không tải được danh sách peer: %v", err)
+	}
+	go peerManager.Run(context.Background())
+	http.HandleFunc("/peers", handlers.NewPeerHandler(peerManager).GetPeersHandler)
+
+	pool := mempool.New(db, mempool.DefaultConfig())
+	txGossip := gossip.New(nodeID, selfAddr, pool, peerManager, gossip.DefaultConfig())
+	go txGossip.Run(context.Background())
+
+	leaderHandler := handlers.NewLeaderHandler(db, engine, pool, txGossip, nodeID)
 	http.HandleFunc("/hello", leaderHandler.Hello)
 	http.HandleFunc("/leader/transaction", leaderHandler.HandleTransaction)
+	http.HandleFunc("/transaction", leaderHandler.HandleTransaction)
 	http.HandleFunc("/mempool", leaderHandler.GetMemPoolHandler)
 	http.HandleFunc("/leader/genBlock", leaderHandler.CreateBlockHandler)
 	http.HandleFunc("/leader/proposal", leaderHandler.SendProposal)
+	http.HandleFunc("/leader/pending", leaderHandler.GetPendingBlockHandler)
+	http.HandleFunc("/leader/status", leaderHandler.GetLeaderStatusHandler)
+
+	if producerCfg := handlers.LoadProducerConfig(); producerCfg.Enabled {
+		go leaderHandler.RunBlockProducer(context.Background(), producerCfg)
+	}
+
+	blockSyncer := syncer.New(db, engine, syncer.LoadConfig())
+	go blockSyncer.Run(context.Background(), peerManager)
 
-	followerHandler := handlers.NewFollowerHandler(db)
+	followerHandler := handlers.NewFollowerHandler(db, engine, blockSyncer, peerManager)
 	http.HandleFunc("/follower/sync", followerHandler.HandleSyncBlock)
+	http.HandleFunc("/sync/status", followerHandler.GetSyncStatusHandler)
 
 	commonHandler := handlers.NewCommonHandler(db)
 	http.HandleFunc("/wallet/new", commonHandler.CreateWalletHandler)
 	http.HandleFunc("/wallet/get", commonHandler.GetWalletHandler)
 	http.HandleFunc("/wallet/getAll", commonHandler.GetAllWalletsHandler)
 	http.HandleFunc("/wallet/getLatesBlock", commonHandler.GetLastBlock)
+	http.HandleFunc("/block", commonHandler.GetBlockByHeight)
+	http.HandleFunc("/blocks", commonHandler.GetBlocks)
+	http.HandleFunc("/blocks/{hash}", commonHandler.GetBlockByHash)
+	http.HandleFunc("/tx/{hash}", commonHandler.GetTransaction)
+	http.HandleFunc("/tx/{hash}/proof", commonHandler.GetTransactionProof)
+	http.HandleFunc("/address/{addr}/txs", commonHandler.GetAddressTxs)
+	http.HandleFunc("/address/{addr}/proof", commonHandler.GetAddressProof)
+	http.HandleFunc("/validators", commonHandler.GetValidatorSet)
 
 	go func() {
 		lis, err := net.Listen("tcp", ":"+tcpPort)
@@ -50,9 +179,14 @@ func main() {
 			log.Fatalf("Không thể lắng nghe: %v", err)
 		}
 
-		grpcServer := grpc.NewServer()
-		proposalServer := service.NewProposalServer(db)
+		grpcServer := grpc.NewServer(
+			grpc.UnaryInterceptor(service.GenesisUnaryInterceptor(genesisBlock.Hash)),
+			grpc.StreamInterceptor(service.GenesisStreamInterceptor(genesisBlock.Hash)),
+		)
+		proposalServer := service.NewProposalServer(db, engine, elect, blockSyncer)
 		pb.RegisterProposalServiceServer(grpcServer, proposalServer)
+		pb.RegisterDiscoveryServer(grpcServer, service.NewDiscoveryServer(db, peerManager, nodeID))
+		pb.RegisterGossipServer(grpcServer, service.NewGossipServer(txGossip, peerManager))
 
 		log.Println("Follower đang lắng nghe ở :" + tcpPort)
 		if err := grpcServer.Serve(lis); err != nil {
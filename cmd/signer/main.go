@@ -0,0 +1,120 @@
+// signer là công cụ phía client: tạo cặp khoá và ký giao dịch ngay trên máy người dùng,
+// để node chỉ nhận public key và chữ ký.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	switch os.Args[1] {
+	case "keygen":
+		keygen()
+	case "sign":
+		sign(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Cách dùng:")
+	fmt.Fprintln(os.Stderr, "  signer keygen")
+	fmt.Fprintln(os.Stderr, "  signer sign -key <private_key_hex> -receiver <addr> -amount <n> [-fee <n>] -nonce <n>")
+	os.Exit(2)
+}
+
+func keygen() {
+	priv, err := network.GenerateKeyPair()
+	if err != nil {
+		log.Fatal(err)
+	}
+	pubKey := network.MarshalPublicKey(&priv.PublicKey)
+
+	printJSON(map[string]string{
+		"private_key": hex.EncodeToString(network.MarshalPrivateKey(priv)),
+		"public_key":  hex.EncodeToString(pubKey),
+		"address":     network.GetAddressFromPubKey(pubKey),
+	})
+}
+
+func sign(args []string) {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	keyHex := fs.String("key", "", "private key (hex)")
+	receiver := fs.String("receiver", "", "địa chỉ nhận")
+	amountStr := fs.String("amount", "", "số token, ví dụ 10 hoặc 0.5")
+	decimals := fs.Uint("decimals", blockchain.DefaultAmountDecimals, "số chữ số thập phân của token (phải khớp với node)")
+	feeStr := fs.String("fee", "0", "phí giao dịch; phí cao hơn được đưa vào block trước")
+	nonce := fs.Uint64("nonce", 0, "nonce kế tiếp của ví gửi (xem GET /wallet/get)")
+	fs.Parse(args)
+
+	if err := blockchain.SetAmountDecimals(uint8(*decimals)); err != nil {
+		log.Fatal(err)
+	}
+	amount, err := blockchain.ParseAmount(*amountStr)
+	if err != nil {
+		log.Fatalf("số tiền không hợp lệ: %v", err)
+	}
+	fee, err := blockchain.ParseAmount(*feeStr)
+	if err != nil {
+		log.Fatalf("phí không hợp lệ: %v", err)
+	}
+	if !blockchain.ValidAddress(*receiver) {
+		log.Fatalf("địa chỉ nhận phải là %d ký tự hex: %q", blockchain.AddressLength, *receiver)
+	}
+
+	keyBytes, err := hex.DecodeString(*keyHex)
+	if err != nil {
+		log.Fatalf("private key không phải hex: %v", err)
+	}
+	priv, err := network.ParsePrivateKey(keyBytes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	pubKey := network.MarshalPublicKey(&priv.PublicKey)
+
+	tx := &blockchain.Transaction{
+		Sender:    network.GetAddressFromPubKey(pubKey),
+		Receiver:  *receiver,
+		Amount:    amount,
+		Fee:       fee,
+		Timestamp: time.Now().Unix(),
+		Nonce:     *nonce,
+		PublicKey: pubKey,
+	}
+	sig, err := network.GenerateSignature(tx, priv)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Đầu ra có thể gửi thẳng tới POST /leader/transaction
+	printJSON(map[string]interface{}{
+		"sender":     tx.Sender,
+		"receiver":   tx.Receiver,
+		"amount":     tx.Amount,
+		"fee":        tx.Fee,
+		"timestamp":  tx.Timestamp,
+		"nonce":      tx.Nonce,
+		"public_key": hex.EncodeToString(pubKey),
+		"signature":  hex.EncodeToString(sig),
+	})
+}
+
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
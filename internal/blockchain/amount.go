@@ -0,0 +1,151 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// DefaultAmountDecimals là số chữ số thập phân mặc định của một token.
+const DefaultAmountDecimals = 6
+
+const maxAmountDecimals = 18
+
+var (
+	ErrAmountOverflow  = errors.New("tràn số khi tính số tiền")
+	ErrAmountUnderflow = errors.New("số tiền bị âm")
+	ErrAmountFormat    = errors.New("định dạng số tiền không hợp lệ")
+)
+
+var amountDecimals uint8 = DefaultAmountDecimals
+
+// SetAmountDecimals cấu hình số chữ số thập phân; chỉ gọi một lần lúc khởi động node.
+// Mọi node (và client ký giao dịch) phải dùng cùng một giá trị.
+func SetAmountDecimals(decimals uint8) error {
+	if decimals > maxAmountDecimals {
+		return fmt.Errorf("số chữ số thập phân tối đa là %d", maxAmountDecimals)
+	}
+	amountDecimals = decimals
+	return nil
+}
+
+func AmountDecimals() uint8 {
+	return amountDecimals
+}
+
+// Amount là số tiền tính theo đơn vị nhỏ nhất (1 token = 10^decimals đơn vị).
+// Mọi phép tính đều là số nguyên nên không có sai số làm tròn.
+type Amount uint64
+
+func unitsPerToken() uint64 {
+	u := uint64(1)
+	for i := uint8(0); i < amountDecimals; i++ {
+		u *= 10
+	}
+	return u
+}
+
+// NewAmount tạo Amount từ số token nguyên.
+func NewAmount(tokens uint64) (Amount, error) {
+	hi, lo := bits.Mul64(tokens, unitsPerToken())
+	if hi != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return Amount(lo), nil
+}
+
+// ParseAmount đọc số token dạng thập phân, ví dụ "10" hoặc "0.25".
+func ParseAmount(s string) (Amount, error) {
+	whole, frac, hasFrac := strings.Cut(strings.TrimSpace(s), ".")
+	if whole == "" && !hasFrac {
+		return 0, ErrAmountFormat
+	}
+	if hasFrac && (frac == "" || len(frac) > int(amountDecimals)) {
+		return 0, ErrAmountFormat
+	}
+	if whole == "" {
+		whole = "0"
+	}
+
+	w, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return 0, ErrAmountOverflow
+		}
+		return 0, ErrAmountFormat
+	}
+	amount, err := NewAmount(w)
+	if err != nil {
+		return 0, err
+	}
+
+	if hasFrac {
+		frac += strings.Repeat("0", int(amountDecimals)-len(frac))
+		f, err := strconv.ParseUint(frac, 10, 64)
+		if err != nil {
+			return 0, ErrAmountFormat
+		}
+		return amount.Add(Amount(f))
+	}
+	return amount, nil
+}
+
+func (a Amount) Add(b Amount) (Amount, error) {
+	sum, carry := bits.Add64(uint64(a), uint64(b), 0)
+	if carry != 0 {
+		return 0, ErrAmountOverflow
+	}
+	return Amount(sum), nil
+}
+
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if b > a {
+		return 0, ErrAmountUnderflow
+	}
+	return a - b, nil
+}
+
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// String trả về số token dạng thập phân, bỏ các số 0 thừa ở cuối.
+func (a Amount) String() string {
+	units := unitsPerToken()
+	whole := uint64(a) / units
+	if amountDecimals == 0 {
+		return strconv.FormatUint(whole, 10)
+	}
+
+	frac := strconv.FormatUint(uint64(a)%units, 10)
+	frac = strings.Repeat("0", int(amountDecimals)-len(frac)) + frac
+	frac = strings.TrimRight(frac, "0")
+	if frac == "" {
+		return strconv.FormatUint(whole, 10)
+	}
+	return strconv.FormatUint(whole, 10) + "." + frac
+}
+
+// MarshalJSON ghi số tiền dạng chuỗi thập phân để không mất độ chính xác ở client JS.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON nhận cả chuỗi ("1.5") lẫn số (10) theo đơn vị token.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	if s == "null" {
+		return nil
+	}
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
@@ -3,58 +3,75 @@ package blockchain
 import (
 	"crypto/sha256"
 	"encoding/hex"
-	"fmt"
+	"math/big"
 )
 
+// BlockVersion là phiên bản cấu trúc header hiện tại.
+// Version 2: cây Merkle phân biệt lá và nút trong (xem merkle.go).
+// Version 3: StateRoot là root của cây trạng thái sau khi áp dụng block (xem stateTree.go).
+const BlockVersion uint32 = 3
+
+// BlockHeader chứa toàn bộ dữ liệu được băm để tạo hash của block.
+type BlockHeader struct {
+	Height     uint64
+	Version    uint32
+	ChainID    string
+	PrevHash   string
+	MerkleRoot string
+	// TxCount là số giao dịch của block; cam kết số lá của cây Merkle để proof không đổi được vị trí
+	TxCount   uint64
+	StateRoot string
+	Timestamp int64
+	Proposer  string
+	Nonce     uint64
+	// Difficulty là số bit 0 tối thiểu ở đầu hash khi dùng proof-of-work (0 nếu không dùng)
+	Difficulty uint32
+	// ValidatorsHash cam kết các tập validator và quorum của genesis (xem Genesis.ValidatorsHash);
+	// chỉ genesis block có trường này, các block khác để rỗng
+	ValidatorsHash string
+}
+
 type Block struct {
-	Timestamp    int64
+	Header       BlockHeader
 	Transactions []Transaction
-	MerkleRoot   string
-	PrevHash     string
-	Nonce        int
 	Hash         string
+	// Certificate chứng minh block đã được quorum validator ký; không nằm trong hash.
+	Certificate *CommitCertificate `json:",omitempty"`
 }
 
-func NewBlock(transactions []Transaction, prevHash string, timestamp int64) *Block {
+// NewBlock tạo block nối tiếp parent; parent nil nghĩa là block đầu tiên (height 0).
+// stateRoot là root của cây trạng thái sau khi áp dụng transactions.
+func NewBlock(parent *Block, transactions []Transaction, stateRoot string, timestamp int64, proposer string) *Block {
+	header := BlockHeader{
+		Version:   BlockVersion,
+		StateRoot: stateRoot,
+		Timestamp: timestamp,
+		Proposer:  proposer,
+	}
+	if parent != nil {
+		header.Height = parent.Header.Height + 1
+		header.PrevHash = parent.Hash
+		header.ChainID = parent.Header.ChainID
+	}
+	header.MerkleRoot = CalculateMerkleRoot(transactions)
+	header.TxCount = uint64(len(transactions))
+
 	block := &Block{
-		Timestamp:    timestamp,
+		Header:       header,
 		Transactions: transactions,
-		PrevHash:     prevHash,
-		Nonce:        0,
 	}
-
-	block.MerkleRoot = CalculateMerkleRoot(transactions)
 	block.Hash = block.CalculateHash()
 
 	return block
 }
 
-func CalculateMerkleRoot(txs []Transaction) string {
-	if len(txs) == 0 {
-		return ""
-	}
-	var hashes [][]byte
-	for _, tx := range txs {
-		hashes = append(hashes, tx.Hash())
-	}
-	for len(hashes) > 1 {
-		var newLevel [][]byte
-		for i := 0; i < len(hashes); i += 2 {
-			if i+1 < len(hashes) {
-				concat := append(hashes[i], hashes[i+1]...)
-				newHash := sha256.Sum256(concat)
-				newLevel = append(newLevel, newHash[:])
-			} else {
-				newLevel = append(newLevel, hashes[i])
-			}
-		}
-		hashes = newLevel
-	}
-	return hex.EncodeToString(hashes[0])
-}
-
 func (b *Block) CalculateHash() string {
-	data := fmt.Sprintf("%d%d%s%s%d", b.Timestamp, b.MerkleRoot, b.PrevHash, b.Nonce)
-	hash := sha256.Sum256([]byte(data))
+	hash := sha256.Sum256(b.Header.Encode())
 	return hex.EncodeToString(hash[:])
 }
+
+// Work là lượng công sức mà block đóng góp cho nhánh chứa nó: 2^Difficulty với block
+// proof-of-work, 1 với block không dùng proof-of-work (khi đó nhánh dài hơn có nhiều work hơn).
+func (b *Block) Work() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(b.Header.Difficulty))
+}
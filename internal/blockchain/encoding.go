@@ -0,0 +1,153 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// EncodingVersion là phiên bản của định dạng mã hoá nhị phân chuẩn.
+// Mọi thay đổi trong thứ tự hay kiểu của trường đều phải tăng phiên bản.
+const EncodingVersion byte = 3
+
+// Tag phân biệt loại dữ liệu để bản mã hoá của giao dịch và header không bao giờ trùng nhau.
+const (
+	encodingTagTransaction byte = 0x01
+	encodingTagBlockHeader byte = 0x02
+	encodingTagVote        byte = 0x03
+	encodingTagAccount     byte = 0x04
+	encodingTagValidators  byte = 0x05
+	encodingTagHeartbeat   byte = 0x06
+	encodingTagVoteRequest byte = 0x07
+)
+
+// encoder ghi các trường theo định dạng chuẩn:
+//   - số nguyên: 8 byte big-endian
+//   - chuỗi / bytes: độ dài 4 byte big-endian, theo sau là nội dung
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func newEncoder(tag byte) *encoder {
+	e := &encoder{}
+	e.buf.WriteByte(EncodingVersion)
+	e.buf.WriteByte(tag)
+	return e
+}
+
+func (e *encoder) writeUint64(v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.buf.Write(b[:])
+}
+
+func (e *encoder) writeInt64(v int64) {
+	e.writeUint64(uint64(v))
+}
+
+func (e *encoder) writeBytes(data []byte) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], uint32(len(data)))
+	e.buf.Write(b[:])
+	e.buf.Write(data)
+}
+
+func (e *encoder) writeString(s string) {
+	e.writeBytes([]byte(s))
+}
+
+func (e *encoder) bytes() []byte {
+	return e.buf.Bytes()
+}
+
+// Encode trả về bản mã hoá chuẩn của giao dịch (không gồm chữ ký).
+// Đây là dữ liệu duy nhất được băm để ký, xác minh và làm lá Merkle.
+func (tx *Transaction) Encode() []byte {
+	e := newEncoder(encodingTagTransaction)
+	e.writeString(tx.Sender)
+	e.writeString(tx.Receiver)
+	e.writeUint64(uint64(tx.Amount))
+	e.writeUint64(uint64(tx.Fee))
+	e.writeInt64(tx.Timestamp)
+	e.writeUint64(tx.Nonce)
+	e.writeBytes(tx.PublicKey)
+	return e.bytes()
+}
+
+// Encode trả về bản mã hoá chuẩn của header; hash của block là SHA-256 của dữ liệu này.
+func (h *BlockHeader) Encode() []byte {
+	e := newEncoder(encodingTagBlockHeader)
+	e.writeUint64(h.Height)
+	e.writeUint64(uint64(h.Version))
+	e.writeString(h.ChainID)
+	e.writeString(h.PrevHash)
+	e.writeString(h.MerkleRoot)
+	e.writeUint64(h.TxCount)
+	e.writeString(h.StateRoot)
+	e.writeInt64(h.Timestamp)
+	e.writeString(h.Proposer)
+	e.writeUint64(h.Nonce)
+	e.writeUint64(uint64(h.Difficulty))
+	e.writeString(h.ValidatorsHash)
+	return e.bytes()
+}
+
+// Encode trả về bản mã hoá chuẩn của phiếu bầu (không gồm validator và chữ ký).
+func (v *Vote) Encode() []byte {
+	e := newEncoder(encodingTagVote)
+	e.writeUint64(v.Height)
+	e.writeUint64(v.Round)
+	e.writeString(v.BlockHash)
+	return e.bytes()
+}
+
+// Encode trả về bản mã hoá chuẩn của heartbeat (không gồm chữ ký).
+func (h *Heartbeat) Encode() []byte {
+	e := newEncoder(encodingTagHeartbeat)
+	e.writeUint64(h.Term)
+	e.writeString(h.LeaderID)
+	e.writeUint64(h.Height)
+	e.writeString(h.LastHash)
+	e.writeInt64(h.Timestamp)
+	return e.bytes()
+}
+
+// Encode trả về bản mã hoá chuẩn của lời xin phiếu (không gồm chữ ký).
+func (r *VoteRequest) Encode() []byte {
+	e := newEncoder(encodingTagVoteRequest)
+	e.writeUint64(r.Term)
+	e.writeString(r.CandidateID)
+	e.writeUint64(r.LastHeight)
+	e.writeString(r.LastHash)
+	return e.bytes()
+}
+
+// Encode trả về bản mã hoá chuẩn của trạng thái tài khoản; lá của cây trạng thái cam kết hash của dữ liệu này.
+func (a AccountState) Encode() []byte {
+	e := newEncoder(encodingTagAccount)
+	e.writeUint64(uint64(a.Balance))
+	e.writeUint64(a.Nonce)
+	return e.bytes()
+}
+
+// EncodeValidators trả về bản mã hoá chuẩn của các tập validator trong genesis (tập ban đầu rồi tới
+// validator_updates). Chỉ gồm những gì quyết định phiếu hợp lệ: height, quorum, id, public key và voting power;
+// địa chỉ của validator không nằm trong bản mã hoá.
+func (g *Genesis) EncodeValidators() []byte {
+	e := newEncoder(encodingTagValidators)
+	e.writeUint64(uint64(1 + len(g.ValidatorUpdates)))
+	writeSet := func(height uint64, quorum string, validators []GenesisValidator) {
+		e.writeUint64(height)
+		e.writeString(quorum)
+		e.writeUint64(uint64(len(validators)))
+		for _, v := range validators {
+			e.writeString(v.ID)
+			e.writeString(v.PublicKey)
+			e.writeUint64(v.Power)
+		}
+	}
+	writeSet(0, g.Quorum, g.Validators)
+	for _, update := range g.ValidatorUpdates {
+		writeSet(update.Height, update.Quorum, update.Validators)
+	}
+	return e.bytes()
+}
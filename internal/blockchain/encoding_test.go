@@ -0,0 +1,162 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+// Các vector dưới đây là vector mẫu trong docs/ENCODING.md; đổi định dạng mã hoá thì phải cập nhật cả tài liệu.
+
+func vectorPublicKey() []byte {
+	pk := make([]byte, 64)
+	for i := range pk {
+		pk[i] = byte(i)
+	}
+	return pk
+}
+
+func vectorTransactions() (Transaction, Transaction) {
+	tx1 := Transaction{
+		Sender:    "alice",
+		Receiver:  "bob",
+		Amount:    10000000,
+		Timestamp: 1700000000,
+		Nonce:     0,
+		PublicKey: vectorPublicKey(),
+	}
+	tx2 := tx1
+	tx2.Amount = 2500000
+	tx2.Nonce = 1
+	return tx1, tx2
+}
+
+func vectorAccounts() StateChanges {
+	return StateChanges{
+		"alice": {Balance: 87500000, Nonce: 2},
+		"bob":   {Balance: 12500000, Nonce: 0},
+	}
+}
+
+func vectorBlock(t *testing.T) *Block {
+	t.Helper()
+	tx1, tx2 := vectorTransactions()
+	root, _, err := UpdateStateRoot(StateNodes{}, EmptyStateRoot, vectorAccounts())
+	if err != nil {
+		t.Fatalf("UpdateStateRoot: %v", err)
+	}
+	return NewBlock(nil, []Transaction{tx1, tx2}, root, 1700000000, "leader-1")
+}
+
+func checkHex(t *testing.T, name string, got []byte, want string) {
+	t.Helper()
+	if hex.EncodeToString(got) != want {
+		t.Errorf("%s = %x, mong đợi %s", name, got, want)
+	}
+}
+
+func TestTransactionEncodingVectors(t *testing.T) {
+	tx1, tx2 := vectorTransactions()
+
+	checkHex(t, "tx1.Encode", tx1.Encode(), "030100000005616c69636500000003626f6200000000009896800000000000000000000000006553f100000000000000000000000040000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f")
+	checkHex(t, "tx1.Hash", tx1.Hash(), "e2ddab772cc75bef1a5d8526273b80de7b903eb305b06e873e29844f49b98d62")
+	checkHex(t, "tx2.Hash", tx2.Hash(), "9b21c1f38be7a1042442ab722f6f59d9158ac0bd3977e8364db7324a3cc699fc")
+
+	// Chữ ký không nằm trong bản mã hoá
+	signed := tx1
+	signed.Signature = []byte{1, 2, 3}
+	if signed.ID() != tx1.ID() {
+		t.Errorf("chữ ký làm đổi ID giao dịch: %s != %s", signed.ID(), tx1.ID())
+	}
+}
+
+func TestStateEncodingVectors(t *testing.T) {
+	accounts := vectorAccounts()
+	alice := accounts["alice"]
+
+	checkHex(t, "alice.Encode", alice.Encode(), "030400000000053724e00000000000000002")
+	checkHex(t, "key(alice)", stateKey("alice"), "2bd806c97f0e00af1a1fc3328fa763a9269723c8db8fac4f93af71db186d6e90")
+	checkHex(t, "key(bob)", stateKey("bob"), "81b637d8fcd2c6da6359e6963113a1170de795e4b725b84d1e0b4cfd9ec58ce9")
+	checkHex(t, "leaf(alice)", stateLeafHash(stateKey("alice"), accountValueHash(alice)), "18c5d96fdf00ae2645048e8d43d2591eb15a250edfe33095778dc3777a237b79")
+
+	nodes := StateNodes{}
+	root, created, err := UpdateStateRoot(nodes, EmptyStateRoot, accounts)
+	if err != nil {
+		t.Fatalf("UpdateStateRoot: %v", err)
+	}
+	if root != "47ad026d8a0d2eb7d0d696a6a1858d30cc396e02f9a858408628f8a768fdfa2c" {
+		t.Errorf("state_root = %s", root)
+	}
+
+	for hash, data := range created {
+		nodes[hash] = data
+	}
+	proof, err := BuildStateProof(nodes, root, "alice")
+	if err != nil {
+		t.Fatalf("BuildStateProof: %v", err)
+	}
+	bob := stateLeafHash(stateKey("bob"), accountValueHash(accounts["bob"]))
+	if len(proof.Siblings) != 1 || proof.Siblings[0] != hex.EncodeToString(bob) {
+		t.Errorf("proof của alice phải có đúng một nút anh em là leaf(bob), nhận %v", proof.Siblings)
+	}
+	if err := VerifyStateProof(proof, root); err != nil {
+		t.Errorf("VerifyStateProof: %v", err)
+	}
+}
+
+func TestBlockHeaderEncodingVectors(t *testing.T) {
+	block := vectorBlock(t)
+
+	if block.Header.MerkleRoot != "63811ddd1544bcf768ee981c42ffa5df0064b798b804c5bf13561ca44b838031" {
+		t.Errorf("merkle_root = %s", block.Header.MerkleRoot)
+	}
+	checkHex(t, "header.Encode", block.Header.Encode(), "0302000000000000000000000000000000030000000000000000000000403633383131646464313534346263663736386565393831633432666661356466303036346237393862383034633562663133353631636134346238333830333100000000000000020000004034376164303236643861306432656237643064363936613661313835386433306363333936653032663961383538343038363238663861373638666466613263000000006553f100000000086c65616465722d310000000000000000000000000000000000000000")
+	if got := block.CalculateHash(); got != "4ac1dc7f9605b257e0481808fa3205a2161b75027f950a4769dd23eccc7e1018" {
+		t.Errorf("hash = %s", got)
+	}
+	if block.Hash != block.CalculateHash() {
+		t.Errorf("NewBlock đặt hash %s khác CalculateHash", block.Hash)
+	}
+}
+
+func TestVoteAndElectionEncodingVectors(t *testing.T) {
+	block := vectorBlock(t)
+
+	vote := Vote{Height: 1, Round: 0, BlockHash: block.Hash, ValidatorID: "leader-1"}
+	checkHex(t, "vote.Encode", vote.Encode(), "0303000000000000000100000000000000000000004034616331646337663936303562323537653034383138303866613332303561323136316237353032376639353061343736396464323365636363376531303138")
+	checkHex(t, "vote.SignBytes", vote.SignBytes(), "16111b5196f217a07dce4ef53b9c3dda1ff275aac3efec65e6acf4a2a1ae8167")
+
+	hb := Heartbeat{Term: 2, LeaderID: "leader-1", Height: 1, LastHash: block.Hash, Timestamp: 1700000000000}
+	checkHex(t, "heartbeat.Encode", hb.Encode(), "03060000000000000002000000086c65616465722d31000000000000000100000040346163316463376639363035623235376530343831383038666133323035613231363162373530323766393530613437363964643233656363633765313031380000018bcfe56800")
+	checkHex(t, "heartbeat.SignBytes", hb.SignBytes(), "ccbb2e6dc42d1dceaee9d3728a48bbde5400792c0753cef7ed6b4901602f8a2a")
+
+	req := VoteRequest{Term: 2, CandidateID: "leader-1", LastHeight: 1, LastHash: block.Hash}
+	checkHex(t, "voteRequest.Encode", req.Encode(), "03070000000000000002000000086c65616465722d3100000000000000010000004034616331646337663936303562323537653034383138303866613332303561323136316237353032376639353061343736396464323365636363376531303138")
+	checkHex(t, "voteRequest.SignBytes", req.SignBytes(), "79ff99265b638e463631953ff738c77c428499a1433b5c0ddd845eca6cf4cb7e")
+}
+
+func TestGenesisValidatorsEncodingVectors(t *testing.T) {
+	g := Genesis{
+		Quorum:     "2/3",
+		Validators: []GenesisValidator{{ID: "leader-1", PublicKey: "0a0b", Power: 1}},
+		ValidatorUpdates: []GenesisValidatorUpdate{{
+			Height:     100,
+			Validators: []GenesisValidator{{ID: "leader-1", PublicKey: "0a0b", Power: 2}},
+		}},
+	}
+	checkHex(t, "genesis.EncodeValidators", g.EncodeValidators(), "03050000000000000002000000000000000000000003322f330000000000000001000000086c65616465722d31000000043061306200000000000000010000000000000064000000000000000000000001000000086c65616465722d3100000004306130620000000000000002")
+	if got := g.ValidatorsHash(); got != "1a1b4e2b59949d3b90029205f9afaee45e02bcdc5f7d27271a3fd517787c99c7" {
+		t.Errorf("validators_hash = %s", got)
+	}
+
+	// Địa chỉ validator không nằm trong hash, quorum thì có
+	moved := g
+	moved.Validators = []GenesisValidator{{ID: "leader-1", Address: "leader:50050", PublicKey: "0a0b", Power: 1}}
+	if moved.ValidatorsHash() != g.ValidatorsHash() {
+		t.Error("đổi địa chỉ validator làm đổi validators_hash")
+	}
+	requorum := g
+	requorum.Quorum = "1/2"
+	if requorum.ValidatorsHash() == g.ValidatorsHash() {
+		t.Error("đổi quorum không làm đổi validators_hash")
+	}
+}
@@ -0,0 +1,170 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// GenesisAllocation là số dư ban đầu của một địa chỉ.
+type GenesisAllocation struct {
+	Address string `json:"address"`
+	Balance Amount `json:"balance"`
+}
+
+// GenesisValidator mô tả một node tham gia bỏ phiếu từ block đầu tiên.
+// Address là địa chỉ gRPC, APIAddress là địa chỉ HTTP; PublicKey được mã hoá hex (X||Y).
+type GenesisValidator struct {
+	ID         string `json:"id"`
+	Address    string `json:"address"`
+	APIAddress string `json:"api_address,omitempty"`
+	PublicKey  string `json:"public_key,omitempty"`
+	Power      uint64 `json:"power"`
+}
+
+// Genesis là đặc tả block đầu tiên của chuỗi; mọi node dùng cùng file này
+// sẽ tạo ra cùng một genesis block (cùng hash).
+type Genesis struct {
+	ChainID     string              `json:"chain_id"`
+	Timestamp   int64               `json:"timestamp"`
+	Allocations []GenesisAllocation `json:"allocations"`
+	Validators  []GenesisValidator  `json:"validators"`
+	// Quorum là tỉ lệ voting power cần vượt qua để commit block, dạng "2/3" (mặc định).
+	Quorum string `json:"quorum,omitempty"`
+	// ValidatorUpdates là các tập validator thay thế tập hiện tại từ một height nhất định, theo height tăng dần.
+	ValidatorUpdates []GenesisValidatorUpdate `json:"validator_updates,omitempty"`
+}
+
+// GenesisValidatorUpdate là tập validator (và quorum) có hiệu lực từ block Height trở đi.
+type GenesisValidatorUpdate struct {
+	Height     uint64             `json:"height"`
+	Validators []GenesisValidator `json:"validators"`
+	Quorum     string             `json:"quorum,omitempty"`
+}
+
+func LoadGenesis(path string) (*Genesis, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var g Genesis
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("file genesis không hợp lệ: %w", err)
+	}
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+func (g *Genesis) Validate() error {
+	if g.ChainID == "" {
+		return errors.New("genesis thiếu chain_id")
+	}
+
+	seen := make(map[string]bool)
+	for _, alloc := range g.Allocations {
+		if !ValidAddress(alloc.Address) || alloc.Balance.IsZero() {
+			return fmt.Errorf("phân bổ genesis không hợp lệ: %+v", alloc)
+		}
+		if seen[alloc.Address] {
+			return fmt.Errorf("địa chỉ %s bị phân bổ hai lần", alloc.Address)
+		}
+		seen[alloc.Address] = true
+	}
+
+	if err := validateGenesisValidators(g.Validators); err != nil {
+		return err
+	}
+	var height uint64
+	for _, update := range g.ValidatorUpdates {
+		if update.Height <= height {
+			return fmt.Errorf("validator_updates phải có height lớn hơn 0 và tăng dần: %d", update.Height)
+		}
+		height = update.Height
+		if err := validateGenesisValidators(update.Validators); err != nil {
+			return fmt.Errorf("validator_updates tại height %d: %w", update.Height, err)
+		}
+	}
+	return nil
+}
+
+func validateGenesisValidators(validators []GenesisValidator) error {
+	ids := make(map[string]bool)
+	for _, v := range validators {
+		if v.ID == "" || ids[v.ID] {
+			return fmt.Errorf("validator genesis trùng hoặc thiếu id: %q", v.ID)
+		}
+		ids[v.ID] = true
+	}
+	return nil
+}
+
+// ValidatorsHash là hash (hex) của EncodeValidators; genesis block ghi hash này vào header để node dùng
+// tập validator hay quorum khác có genesis hash khác.
+func (g *Genesis) ValidatorsHash() string {
+	hash := sha256.Sum256(g.EncodeValidators())
+	return hex.EncodeToString(hash[:])
+}
+
+// Block tạo genesis block: mỗi phân bổ là một giao dịch phát hành (Sender rỗng)
+// để Merkle root cam kết toàn bộ số dư ban đầu; state root là cây trạng thái của các số dư đó.
+// Header cũng cam kết các tập validator và quorum qua ValidatorsHash.
+func (g *Genesis) Block() *Block {
+	txs := make([]Transaction, 0, len(g.Allocations))
+	state := StateChanges{}
+	for _, alloc := range g.Allocations {
+		txs = append(txs, Transaction{
+			Receiver:  alloc.Address,
+			Amount:    alloc.Balance,
+			Timestamp: g.Timestamp,
+		})
+		state[alloc.Address] = AccountState{Balance: alloc.Balance}
+	}
+	// Cây rỗng nên không có nút nào cần đọc; UpdateStateRoot không thể lỗi
+	stateRoot, _, _ := UpdateStateRoot(StateNodes{}, EmptyStateRoot, state)
+
+	block := &Block{
+		Header: BlockHeader{
+			Height:         0,
+			Version:        BlockVersion,
+			ChainID:        g.ChainID,
+			MerkleRoot:     CalculateMerkleRoot(txs),
+			TxCount:        uint64(len(txs)),
+			StateRoot:      stateRoot,
+			Timestamp:      g.Timestamp,
+			ValidatorsHash: g.ValidatorsHash(),
+		},
+		Transactions: txs,
+	}
+	block.Hash = block.CalculateHash()
+	return block
+}
+
+// ApplyGenesisTransactions cộng số dư cho các giao dịch phát hành của genesis block.
+func ApplyGenesisTransactions(state StateReader, txs []Transaction) (StateChanges, error) {
+	changes := StateChanges{}
+	for i, tx := range txs {
+		if tx.Sender != "" {
+			return nil, fmt.Errorf("giao dịch genesis #%d không phải giao dịch phát hành", i)
+		}
+
+		acc, ok := changes[tx.Receiver]
+		if !ok {
+			var err error
+			if acc, err = state.GetAccountState(tx.Receiver); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if acc.Balance, err = acc.Balance.Add(tx.Amount); err != nil {
+			return nil, fmt.Errorf("giao dịch genesis #%d: %w", i, err)
+		}
+		changes[tx.Receiver] = acc
+	}
+	return changes, nil
+}
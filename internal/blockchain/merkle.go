@@ -0,0 +1,145 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Cây Merkle theo RFC 6962: lá và nút trong được băm với tiền tố khác nhau, nên một nút trong không
+// thể được trình bày như một giao dịch. Nút lẻ cuối mỗi tầng được đưa thẳng lên tầng trên chứ không
+// nhân đôi, nên không có hai danh sách giao dịch khác nhau cho cùng một root (CVE-2012-2459).
+const (
+	merkleLeafPrefix = 0x00
+	merkleNodePrefix = 0x01
+)
+
+var ErrInvalidMerkleProof = errors.New("Merkle proof không hợp lệ")
+
+func merkleLeaf(txHash []byte) []byte {
+	h := sha256.Sum256(append([]byte{merkleLeafPrefix}, txHash...))
+	return h[:]
+}
+
+func merkleNode(left, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(buf, merkleNodePrefix)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+	h := sha256.Sum256(buf)
+	return h[:]
+}
+
+// merkleLevels trả về mọi tầng của cây, tầng 0 là các lá.
+func merkleLevels(txs []Transaction) [][][]byte {
+	level := make([][]byte, 0, len(txs))
+	for i := range txs {
+		level = append(level, merkleLeaf(txs[i].Hash()))
+	}
+	levels := [][][]byte{level}
+	for len(level) > 1 {
+		next := make([][]byte, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 < len(level) {
+				next = append(next, merkleNode(level[i], level[i+1]))
+			} else {
+				next = append(next, level[i])
+			}
+		}
+		levels = append(levels, next)
+		level = next
+	}
+	return levels
+}
+
+func CalculateMerkleRoot(txs []Transaction) string {
+	if len(txs) == 0 {
+		return ""
+	}
+	levels := merkleLevels(txs)
+	return hex.EncodeToString(levels[len(levels)-1][0])
+}
+
+// MerkleProof chứng minh giao dịch TxHash nằm ở vị trí Index trong block có LeafCount giao dịch.
+// Siblings là hash các nút anh em (hex) từ lá đi lên; tầng mà nút được đưa thẳng lên không có anh em.
+type MerkleProof struct {
+	TxHash    string   `json:"tx_hash"`
+	Index     int      `json:"index"`
+	LeafCount int      `json:"leaf_count"`
+	Siblings  []string `json:"siblings"`
+}
+
+// BuildMerkleProof tạo proof cho giao dịch thứ index của txs.
+func BuildMerkleProof(txs []Transaction, index int) (*MerkleProof, error) {
+	if index < 0 || index >= len(txs) {
+		return nil, fmt.Errorf("vị trí giao dịch %d nằm ngoài block có %d giao dịch", index, len(txs))
+	}
+	proof := &MerkleProof{TxHash: txs[index].ID(), Index: index, LeafCount: len(txs), Siblings: []string{}}
+	levels := merkleLevels(txs)
+	i := index
+	for _, level := range levels[:len(levels)-1] {
+		sibling := i ^ 1
+		if sibling < len(level) {
+			proof.Siblings = append(proof.Siblings, hex.EncodeToString(level[sibling]))
+		}
+		i /= 2
+	}
+	return proof, nil
+}
+
+// VerifyTxProof kiểm tra proof của giao dịch trong block có header h. Hình dạng cây phụ thuộc vào số lá
+// nên LeafCount phải bằng TxCount của header; nếu không, proof của một giao dịch có thể được trình bày
+// lại như proof ở vị trí khác trong một cây nhỏ hơn mà vẫn ra cùng root.
+func (h *BlockHeader) VerifyTxProof(proof *MerkleProof) error {
+	if uint64(proof.LeafCount) != h.TxCount {
+		return fmt.Errorf("%w: proof ghi %d giao dịch, header ghi %d", ErrInvalidMerkleProof, proof.LeafCount, h.TxCount)
+	}
+	return VerifyMerkleProof(proof, h.MerkleRoot)
+}
+
+// VerifyMerkleProof kiểm tra proof dẫn từ giao dịch tới root (MerkleRoot của header). Hàm không biết
+// số giao dịch thật của block nên không xác nhận được Index; kiểm tra theo header thì dùng VerifyTxProof.
+func VerifyMerkleProof(proof *MerkleProof, root string) error {
+	if proof.LeafCount <= 0 || proof.Index < 0 || proof.Index >= proof.LeafCount {
+		return fmt.Errorf("%w: vị trí %d, số giao dịch %d", ErrInvalidMerkleProof, proof.Index, proof.LeafCount)
+	}
+	txHash, err := hex.DecodeString(proof.TxHash)
+	if err != nil {
+		return fmt.Errorf("%w: tx_hash không phải hex", ErrInvalidMerkleProof)
+	}
+	expected, err := hex.DecodeString(root)
+	if err != nil {
+		return fmt.Errorf("%w: root không phải hex", ErrInvalidMerkleProof)
+	}
+
+	hash := merkleLeaf(txHash)
+	used := 0
+	for i, n := proof.Index, proof.LeafCount; n > 1; i, n = i/2, (n+1)/2 {
+		if i%2 == 0 && i+1 == n {
+			// Nút lẻ cuối tầng, được đưa thẳng lên
+			continue
+		}
+		if used >= len(proof.Siblings) {
+			return fmt.Errorf("%w: thiếu nút anh em", ErrInvalidMerkleProof)
+		}
+		sibling, err := hex.DecodeString(proof.Siblings[used])
+		if err != nil || len(sibling) != sha256.Size {
+			return fmt.Errorf("%w: nút anh em %d không hợp lệ", ErrInvalidMerkleProof, used)
+		}
+		used++
+		if i%2 == 0 {
+			hash = merkleNode(hash, sibling)
+		} else {
+			hash = merkleNode(sibling, hash)
+		}
+	}
+	if used != len(proof.Siblings) {
+		return fmt.Errorf("%w: thừa %d nút anh em", ErrInvalidMerkleProof, len(proof.Siblings)-used)
+	}
+	if !bytes.Equal(hash, expected) {
+		return fmt.Errorf("%w: root không khớp", ErrInvalidMerkleProof)
+	}
+	return nil
+}
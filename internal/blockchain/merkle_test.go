@@ -0,0 +1,197 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func merkleTestTxs(n int) []Transaction {
+	txs := make([]Transaction, n)
+	for i := range txs {
+		txs[i] = Transaction{Sender: "alice", Receiver: "bob", Amount: 1, Timestamp: 1700000000, Nonce: uint64(i)}
+	}
+	return txs
+}
+
+func leafHex(tx Transaction) string {
+	return hex.EncodeToString(merkleLeaf(tx.Hash()))
+}
+
+func TestMerkleRootSmallTrees(t *testing.T) {
+	txs := merkleTestTxs(3)
+	a, b, c := merkleLeaf(txs[0].Hash()), merkleLeaf(txs[1].Hash()), merkleLeaf(txs[2].Hash())
+
+	tests := []struct {
+		name string
+		txs  []Transaction
+		want string
+	}{
+		{"không có giao dịch", nil, ""},
+		{"một lá", txs[:1], hex.EncodeToString(a)},
+		{"hai lá", txs[:2], hex.EncodeToString(merkleNode(a, b))},
+		{"ba lá, lá lẻ được đưa thẳng lên", txs, hex.EncodeToString(merkleNode(merkleNode(a, b), c))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CalculateMerkleRoot(tt.txs); got != tt.want {
+				t.Errorf("CalculateMerkleRoot = %s, mong đợi %s", got, tt.want)
+			}
+		})
+	}
+
+	// Lá không phải là hash giao dịch: một nút trong không thể được trình bày như một giao dịch
+	if CalculateMerkleRoot(txs[:1]) == txs[0].ID() {
+		t.Error("root cây một lá bằng hash giao dịch, lá không có tiền tố")
+	}
+}
+
+func TestMerkleProofEveryPosition(t *testing.T) {
+	for n := 1; n <= 9; n++ {
+		txs := merkleTestTxs(n)
+		header := NewBlock(nil, txs, EmptyStateRoot, 1700000000, "leader-1").Header
+		for i := range txs {
+			proof, err := BuildMerkleProof(txs, i)
+			if err != nil {
+				t.Fatalf("BuildMerkleProof(%d lá, %d): %v", n, i, err)
+			}
+			if err := header.VerifyTxProof(proof); err != nil {
+				t.Errorf("proof vị trí %d trong %d lá: %v", i, n, err)
+			}
+			if n == 1 && len(proof.Siblings) != 0 {
+				t.Errorf("cây một lá có proof %v, mong đợi không có nút anh em", proof.Siblings)
+			}
+			if n == 2 && (len(proof.Siblings) != 1 || proof.Siblings[0] != leafHex(txs[1-i])) {
+				t.Errorf("proof vị trí %d trong cây hai lá = %v, mong đợi lá còn lại", i, proof.Siblings)
+			}
+		}
+	}
+}
+
+func TestMerkleProofOutOfRange(t *testing.T) {
+	txs := merkleTestTxs(3)
+	for _, index := range []int{-1, 3, 4} {
+		if _, err := BuildMerkleProof(txs, index); err == nil {
+			t.Errorf("BuildMerkleProof(%d) trong 3 lá không báo lỗi", index)
+		}
+	}
+	if _, err := BuildMerkleProof(nil, 0); err == nil {
+		t.Error("BuildMerkleProof trong block rỗng không báo lỗi")
+	}
+
+	root := CalculateMerkleRoot(txs)
+	proof, err := BuildMerkleProof(txs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		index     int
+		leafCount int
+	}{
+		{"index âm", -1, 3},
+		{"index bằng số lá", 3, 3},
+		{"không có lá", 0, 0},
+		{"số lá âm", 0, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := *proof
+			bad.Index, bad.LeafCount = tt.index, tt.leafCount
+			if err := VerifyMerkleProof(&bad, root); !errors.Is(err, ErrInvalidMerkleProof) {
+				t.Errorf("VerifyMerkleProof = %v, mong đợi %v", err, ErrInvalidMerkleProof)
+			}
+		})
+	}
+}
+
+// Với cây nhân đôi nút lẻ (như Bitcoin), [a, b, c] và [a, b, c, c] có cùng root (CVE-2012-2459).
+func TestMerkleRootDuplicatedLastLeaf(t *testing.T) {
+	for _, n := range []int{1, 3, 5, 6} {
+		txs := merkleTestTxs(n)
+		duplicated := append(append([]Transaction(nil), txs...), txs[n-1])
+		if CalculateMerkleRoot(txs) == CalculateMerkleRoot(duplicated) {
+			t.Errorf("%d lá: nhân đôi lá cuối không làm đổi root", n)
+		}
+	}
+
+	// Nhân đôi cả cặp cuối của cây 6 lá cũng phải cho root khác
+	txs := merkleTestTxs(6)
+	duplicated := append(append([]Transaction(nil), txs...), txs[4], txs[5])
+	if CalculateMerkleRoot(txs) == CalculateMerkleRoot(duplicated) {
+		t.Error("nhân đôi cặp lá cuối không làm đổi root")
+	}
+}
+
+func TestMerkleProofForgedLeafCount(t *testing.T) {
+	txs := merkleTestTxs(3)
+	header := NewBlock(nil, txs, EmptyStateRoot, 1700000000, "leader-1").Header
+	proof, err := BuildMerkleProof(txs, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Lá cuối của cây 3 lá được đưa thẳng lên, nên cùng nút anh em đó nó cũng là lá thứ 1 của cây 2 lá
+	forged := *proof
+	forged.Index, forged.LeafCount = 1, 2
+	if err := VerifyMerkleProof(&forged, header.MerkleRoot); err != nil {
+		t.Fatalf("ví dụ giả mạo không còn khớp root, cần cập nhật test: %v", err)
+	}
+	if err := header.VerifyTxProof(&forged); !errors.Is(err, ErrInvalidMerkleProof) {
+		t.Errorf("VerifyTxProof chấp nhận proof có leaf_count giả: %v", err)
+	}
+
+	// Theo header, proof chỉ hợp lệ ở đúng vị trí thật với đúng số lá
+	for n := 1; n <= 8; n++ {
+		txs := merkleTestTxs(n)
+		header := NewBlock(nil, txs, EmptyStateRoot, 1700000000, "leader-1").Header
+		for i := range txs {
+			proof, err := BuildMerkleProof(txs, i)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for count := 1; count <= 8; count++ {
+				for index := 0; index < count; index++ {
+					if count == n && index == i {
+						continue
+					}
+					moved := *proof
+					moved.Index, moved.LeafCount = index, count
+					if header.VerifyTxProof(&moved) == nil {
+						t.Errorf("proof vị trí %d/%d được chấp nhận ở vị trí %d/%d", i, n, index, count)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestMerkleProofTampered(t *testing.T) {
+	txs := merkleTestTxs(5)
+	header := NewBlock(nil, txs, EmptyStateRoot, 1700000000, "leader-1").Header
+	proof, err := BuildMerkleProof(txs, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(p *MerkleProof)
+	}{
+		{"giao dịch khác", func(p *MerkleProof) { p.TxHash = txs[0].ID() }},
+		{"đổi nút anh em", func(p *MerkleProof) { p.Siblings[1] = leafHex(txs[4]) }},
+		{"thiếu nút anh em", func(p *MerkleProof) { p.Siblings = p.Siblings[:len(p.Siblings)-1] }},
+		{"thừa nút anh em", func(p *MerkleProof) { p.Siblings = append(p.Siblings, leafHex(txs[4])) }},
+		{"nút anh em không phải hex", func(p *MerkleProof) { p.Siblings[0] = "zz" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := *proof
+			bad.Siblings = append([]string(nil), proof.Siblings...)
+			tt.tamper(&bad)
+			if err := header.VerifyTxProof(&bad); !errors.Is(err, ErrInvalidMerkleProof) {
+				t.Errorf("VerifyTxProof = %v, mong đợi %v", err, ErrInvalidMerkleProof)
+			}
+		})
+	}
+}
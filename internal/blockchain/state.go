@@ -0,0 +1,103 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInsufficientBalance = errors.New("số dư không đủ")
+	ErrInvalidAmount       = errors.New("số tiền không hợp lệ")
+	ErrInvalidNonce        = errors.New("nonce không hợp lệ (trùng hoặc sai thứ tự)")
+	ErrInvalidAddress      = errors.New("địa chỉ không hợp lệ")
+)
+
+// AccountState là trạng thái của một tài khoản trên sổ cái.
+// Nonce là nonce mà giao dịch kế tiếp của tài khoản phải mang.
+type AccountState struct {
+	Balance Amount
+	Nonce   uint64
+}
+
+// StateReader cung cấp trạng thái tài khoản hiện tại (trước khi áp dụng block).
+type StateReader interface {
+	GetAccountState(address string) (AccountState, error)
+}
+
+// StateChanges chứa trạng thái mới của các tài khoản bị thay đổi bởi một block.
+type StateChanges map[string]AccountState
+
+// ApplyTransactions áp dụng tuần tự các giao dịch lên trạng thái hiện tại và trả về
+// các tài khoản bị thay đổi. Nếu bất kỳ giao dịch nào làm âm số dư thì toàn bộ bị từ chối.
+func ApplyTransactions(state StateReader, txs []Transaction) (StateChanges, error) {
+	changes := StateChanges{}
+
+	load := func(address string) (AccountState, error) {
+		if acc, ok := changes[address]; ok {
+			return acc, nil
+		}
+		return state.GetAccountState(address)
+	}
+
+	for i, tx := range txs {
+		if tx.Amount.IsZero() {
+			return nil, fmt.Errorf("giao dịch #%d: %w", i, ErrInvalidAmount)
+		}
+		if !ValidAddress(tx.Receiver) {
+			return nil, fmt.Errorf("giao dịch #%d: %w: người nhận %q", i, ErrInvalidAddress, tx.Receiver)
+		}
+
+		sender, err := load(tx.Sender)
+		if err != nil {
+			return nil, fmt.Errorf("giao dịch #%d: không đọc được ví gửi: %w", i, err)
+		}
+		if tx.Nonce != sender.Nonce {
+			return nil, fmt.Errorf("giao dịch #%d (%s): %w: mong đợi %d, nhận %d", i, tx.Sender, ErrInvalidNonce, sender.Nonce, tx.Nonce)
+		}
+		cost, err := tx.Cost()
+		if err != nil {
+			return nil, fmt.Errorf("giao dịch #%d: %w", i, err)
+		}
+		// Phí bị đốt: trừ khỏi người gửi nhưng không cộng cho ai
+		if sender.Balance, err = sender.Balance.Sub(cost); err != nil {
+			return nil, fmt.Errorf("giao dịch #%d (%s): %w", i, tx.Sender, ErrInsufficientBalance)
+		}
+		sender.Nonce++
+		changes[tx.Sender] = sender
+
+		receiver, err := load(tx.Receiver)
+		if err != nil {
+			return nil, fmt.Errorf("giao dịch #%d: không đọc được ví nhận: %w", i, err)
+		}
+		if receiver.Balance, err = receiver.Balance.Add(tx.Amount); err != nil {
+			return nil, fmt.Errorf("giao dịch #%d (%s): %w", i, tx.Receiver, err)
+		}
+		changes[tx.Receiver] = receiver
+	}
+
+	return changes, nil
+}
+
+// Merge ghi đè các thay đổi của other lên c (other được áp dụng sau c).
+func (c StateChanges) Merge(other StateChanges) {
+	for address, acc := range other {
+		c[address] = acc
+	}
+}
+
+// Overlay trả về StateReader đọc trạng thái trong c trước, sau đó mới tới base.
+func (c StateChanges) Overlay(base StateReader) StateReader {
+	return overlayState{base: base, changes: c}
+}
+
+type overlayState struct {
+	base    StateReader
+	changes StateChanges
+}
+
+func (o overlayState) GetAccountState(address string) (AccountState, error) {
+	if acc, ok := o.changes[address]; ok {
+		return acc, nil
+	}
+	return o.base.GetAccountState(address)
+}
@@ -0,0 +1,374 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Cây trạng thái là sparse Merkle tree 256 tầng: tài khoản nằm ở lá có đường đi là các bit của
+// SHA-256(address). Cây con rỗng có hash 32 byte 0, cây con chỉ có một tài khoản được thay bằng
+// chính lá của tài khoản đó, cây con có từ hai tài khoản trở lên là nút trong. Nhờ vậy root chỉ phụ
+// thuộc vào tập tài khoản chứ không phụ thuộc thứ tự cập nhật, và mỗi proof có tối đa 256 nút anh em.
+//
+// Nút được lưu theo hash nên mọi root cũ vẫn đọc được: nút lá là 0x00 | key | balance | nonce,
+// nút trong là 0x01 | trái | phải.
+const (
+	stateLeafPrefix = 0x00
+	stateNodePrefix = 0x01
+
+	stateKeySize  = sha256.Size
+	stateTreeBits = stateKeySize * 8
+)
+
+var (
+	ErrInvalidStateProof = errors.New("state proof không hợp lệ")
+	ErrStateNodeNotFound = errors.New("không tìm thấy nút của cây trạng thái")
+)
+
+// EmptyStateRoot là root của cây trạng thái không có tài khoản nào.
+var EmptyStateRoot = hex.EncodeToString(make([]byte, sha256.Size))
+
+// StateNodeReader đọc nút của cây trạng thái theo hash; trả về ErrStateNodeNotFound nếu không có.
+type StateNodeReader interface {
+	GetStateNode(hash []byte) ([]byte, error)
+}
+
+// StateNodes là các nút mới tạo ra khi cập nhật cây, khoá là hash của nút.
+type StateNodes map[string][]byte
+
+func (n StateNodes) GetStateNode(hash []byte) ([]byte, error) {
+	if data, ok := n[string(hash)]; ok {
+		return data, nil
+	}
+	return nil, ErrStateNodeNotFound
+}
+
+// Merge thêm các nút của other vào n.
+func (n StateNodes) Merge(other StateNodes) {
+	for hash, data := range other {
+		n[hash] = data
+	}
+}
+
+// Overlay trả về StateNodeReader đọc nút trong n trước, sau đó mới tới base.
+func (n StateNodes) Overlay(base StateNodeReader) StateNodeReader {
+	return overlayNodes{base: base, nodes: n}
+}
+
+type overlayNodes struct {
+	base  StateNodeReader
+	nodes StateNodes
+}
+
+func (o overlayNodes) GetStateNode(hash []byte) ([]byte, error) {
+	if data, ok := o.nodes[string(hash)]; ok {
+		return data, nil
+	}
+	return o.base.GetStateNode(hash)
+}
+
+func stateKey(address string) []byte {
+	h := sha256.Sum256([]byte(address))
+	return h[:]
+}
+
+// stateBit trả về bit thứ depth (tính từ bit cao nhất) của key: 0 là nhánh trái, 1 là nhánh phải.
+func stateBit(key []byte, depth int) int {
+	return int(key[depth/8]>>(7-depth%8)) & 1
+}
+
+func stateLeafHash(key, valueHash []byte) []byte {
+	buf := make([]byte, 0, 1+len(key)+len(valueHash))
+	buf = append(buf, stateLeafPrefix)
+	buf = append(buf, key...)
+	buf = append(buf, valueHash...)
+	h := sha256.Sum256(buf)
+	return h[:]
+}
+
+func accountValueHash(acc AccountState) []byte {
+	h := sha256.Sum256(acc.Encode())
+	return h[:]
+}
+
+func isEmptyStateNode(hash []byte) bool {
+	return bytes.Equal(hash, make([]byte, sha256.Size))
+}
+
+type stateLeaf struct {
+	key     []byte
+	account AccountState
+}
+
+func (l stateLeaf) hash() []byte {
+	return stateLeafHash(l.key, accountValueHash(l.account))
+}
+
+func (l stateLeaf) encode() []byte {
+	buf := make([]byte, 0, 1+stateKeySize+16)
+	buf = append(buf, stateLeafPrefix)
+	buf = append(buf, l.key...)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(l.account.Balance))
+	buf = binary.BigEndian.AppendUint64(buf, l.account.Nonce)
+	return buf
+}
+
+// stateNode là một nút đã giải mã: lá (leaf khác nil) hoặc nút trong.
+type stateNode struct {
+	leaf        *stateLeaf
+	left, right []byte
+}
+
+func decodeStateNode(data []byte) (*stateNode, error) {
+	switch {
+	case len(data) == 1+stateKeySize+16 && data[0] == stateLeafPrefix:
+		return &stateNode{leaf: &stateLeaf{
+			key: data[1 : 1+stateKeySize],
+			account: AccountState{
+				Balance: Amount(binary.BigEndian.Uint64(data[1+stateKeySize:])),
+				Nonce:   binary.BigEndian.Uint64(data[1+stateKeySize+8:]),
+			},
+		}}, nil
+	case len(data) == 1+2*sha256.Size && data[0] == stateNodePrefix:
+		return &stateNode{left: data[1 : 1+sha256.Size], right: data[1+sha256.Size:]}, nil
+	default:
+		return nil, fmt.Errorf("nút cây trạng thái không hợp lệ (%d byte)", len(data))
+	}
+}
+
+type stateTree struct {
+	nodes   StateNodeReader
+	created StateNodes
+}
+
+func (t *stateTree) load(hash []byte) (*stateNode, error) {
+	data, err := t.nodes.GetStateNode(hash)
+	if err != nil {
+		return nil, fmt.Errorf("nút %x: %w", hash, err)
+	}
+	return decodeStateNode(data)
+}
+
+func (t *stateTree) putLeaf(leaf stateLeaf) []byte {
+	hash := leaf.hash()
+	t.created[string(hash)] = leaf.encode()
+	return hash
+}
+
+func (t *stateTree) putNode(left, right []byte) []byte {
+	hash := merkleNode(left, right)
+	data := make([]byte, 0, 1+len(left)+len(right))
+	data = append(data, stateNodePrefix)
+	data = append(data, left...)
+	data = append(data, right...)
+	t.created[string(hash)] = data
+	return hash
+}
+
+// update ghi leaves (cùng chung depth bit đầu) vào cây con có hash tại độ sâu depth.
+func (t *stateTree) update(hash []byte, depth int, leaves []stateLeaf) ([]byte, error) {
+	if len(leaves) == 0 {
+		return hash, nil
+	}
+	if isEmptyStateNode(hash) {
+		return t.build(depth, leaves), nil
+	}
+
+	node, err := t.load(hash)
+	if err != nil {
+		return nil, err
+	}
+	if node.leaf != nil {
+		// Tài khoản cũ được giữ lại nếu không bị cập nhật
+		updated := false
+		for _, leaf := range leaves {
+			if bytes.Equal(leaf.key, node.leaf.key) {
+				updated = true
+				break
+			}
+		}
+		if !updated {
+			leaves = append(leaves, *node.leaf)
+		}
+		return t.build(depth, leaves), nil
+	}
+
+	left, right := splitStateLeaves(leaves, depth)
+	newLeft, err := t.update(node.left, depth+1, left)
+	if err != nil {
+		return nil, err
+	}
+	newRight, err := t.update(node.right, depth+1, right)
+	if err != nil {
+		return nil, err
+	}
+	return t.putNode(newLeft, newRight), nil
+}
+
+// build tạo cây con mới tại độ sâu depth chỉ gồm leaves.
+func (t *stateTree) build(depth int, leaves []stateLeaf) []byte {
+	switch len(leaves) {
+	case 0:
+		return make([]byte, sha256.Size)
+	case 1:
+		return t.putLeaf(leaves[0])
+	}
+	left, right := splitStateLeaves(leaves, depth)
+	return t.putNode(t.build(depth+1, left), t.build(depth+1, right))
+}
+
+func splitStateLeaves(leaves []stateLeaf, depth int) (left, right []stateLeaf) {
+	for _, leaf := range leaves {
+		if stateBit(leaf.key, depth) == 0 {
+			left = append(left, leaf)
+		} else {
+			right = append(right, leaf)
+		}
+	}
+	return left, right
+}
+
+// UpdateStateRoot áp dụng changes lên cây trạng thái có root và trả về root mới cùng các nút mới
+// cần lưu. Cây cũ không bị sửa nên root cũ vẫn dùng được.
+func UpdateStateRoot(nodes StateNodeReader, root string, changes StateChanges) (string, StateNodes, error) {
+	rootHash, err := hex.DecodeString(root)
+	if err != nil || len(rootHash) != sha256.Size {
+		return "", nil, fmt.Errorf("state root %q không hợp lệ", root)
+	}
+
+	leaves := make([]stateLeaf, 0, len(changes))
+	for address, acc := range changes {
+		leaves = append(leaves, stateLeaf{key: stateKey(address), account: acc})
+	}
+	tree := &stateTree{nodes: nodes, created: StateNodes{}}
+	newRoot, err := tree.update(rootHash, 0, leaves)
+	if err != nil {
+		return "", nil, err
+	}
+	return hex.EncodeToString(newRoot), tree.created, nil
+}
+
+// CheckStateRoot tính root sau khi áp dụng changes của block lên cây trạng thái của block cha
+// và so với StateRoot trong header. Trả về các nút mới cần lưu nếu khớp.
+func CheckStateRoot(nodes StateNodeReader, parent *Block, block *Block, changes StateChanges) (StateNodes, error) {
+	root, created, err := UpdateStateRoot(nodes, parent.Header.StateRoot, changes)
+	if err != nil {
+		return nil, err
+	}
+	if root != block.Header.StateRoot {
+		return nil, reject(ReasonBadStateRoot, "state root %s khác root sau khi áp dụng block %s", block.Header.StateRoot, root)
+	}
+	return created, nil
+}
+
+// StateProof chứng minh trạng thái của Address trong cây có root cho trước. Account nil nghĩa là
+// tài khoản chưa tồn tại; khi đó đường đi kết thúc ở cây con rỗng, hoặc ở lá của một tài khoản khác
+// (OtherKey, OtherValueHash). Siblings là hash các nút anh em (hex) từ gốc đi xuống.
+type StateProof struct {
+	Address        string        `json:"address"`
+	Account        *AccountState `json:"account"`
+	Siblings       []string      `json:"siblings"`
+	OtherKey       string        `json:"other_key,omitempty"`
+	OtherValueHash string        `json:"other_value_hash,omitempty"`
+}
+
+// BuildStateProof tạo proof cho address trong cây có root.
+func BuildStateProof(nodes StateNodeReader, root, address string) (*StateProof, error) {
+	hash, err := hex.DecodeString(root)
+	if err != nil || len(hash) != sha256.Size {
+		return nil, fmt.Errorf("state root %q không hợp lệ", root)
+	}
+
+	tree := &stateTree{nodes: nodes}
+	key := stateKey(address)
+	proof := &StateProof{Address: address, Siblings: []string{}}
+	for depth := 0; !isEmptyStateNode(hash); depth++ {
+		node, err := tree.load(hash)
+		if err != nil {
+			return nil, err
+		}
+		if node.leaf != nil {
+			if bytes.Equal(node.leaf.key, key) {
+				acc := node.leaf.account
+				proof.Account = &acc
+			} else {
+				proof.OtherKey = hex.EncodeToString(node.leaf.key)
+				proof.OtherValueHash = hex.EncodeToString(accountValueHash(node.leaf.account))
+			}
+			break
+		}
+		if depth >= stateTreeBits {
+			return nil, fmt.Errorf("cây trạng thái sâu quá %d tầng", stateTreeBits)
+		}
+		if stateBit(key, depth) == 0 {
+			proof.Siblings = append(proof.Siblings, hex.EncodeToString(node.right))
+			hash = node.left
+		} else {
+			proof.Siblings = append(proof.Siblings, hex.EncodeToString(node.left))
+			hash = node.right
+		}
+	}
+	return proof, nil
+}
+
+// VerifyStateProof kiểm tra proof dẫn từ trạng thái của tài khoản (hoặc sự vắng mặt của nó) tới root
+// (StateRoot của header).
+func VerifyStateProof(proof *StateProof, root string) error {
+	expected, err := hex.DecodeString(root)
+	if err != nil {
+		return fmt.Errorf("%w: root không phải hex", ErrInvalidStateProof)
+	}
+	if len(proof.Siblings) > stateTreeBits {
+		return fmt.Errorf("%w: quá %d nút anh em", ErrInvalidStateProof, stateTreeBits)
+	}
+
+	key := stateKey(proof.Address)
+	var hash []byte
+	switch {
+	case proof.Account != nil:
+		if proof.OtherKey != "" || proof.OtherValueHash != "" {
+			return fmt.Errorf("%w: có cả tài khoản và lá khác", ErrInvalidStateProof)
+		}
+		hash = stateLeafHash(key, accountValueHash(*proof.Account))
+	case proof.OtherKey != "":
+		otherKey, err := hex.DecodeString(proof.OtherKey)
+		if err != nil || len(otherKey) != stateKeySize {
+			return fmt.Errorf("%w: other_key không hợp lệ", ErrInvalidStateProof)
+		}
+		valueHash, err := hex.DecodeString(proof.OtherValueHash)
+		if err != nil || len(valueHash) != sha256.Size {
+			return fmt.Errorf("%w: other_value_hash không hợp lệ", ErrInvalidStateProof)
+		}
+		if bytes.Equal(otherKey, key) {
+			return fmt.Errorf("%w: lá khác trùng key của tài khoản", ErrInvalidStateProof)
+		}
+		for depth := range proof.Siblings {
+			if stateBit(otherKey, depth) != stateBit(key, depth) {
+				return fmt.Errorf("%w: lá khác không nằm trên đường đi của tài khoản", ErrInvalidStateProof)
+			}
+		}
+		hash = stateLeafHash(otherKey, valueHash)
+	default:
+		hash = make([]byte, sha256.Size)
+	}
+
+	for depth := len(proof.Siblings) - 1; depth >= 0; depth-- {
+		sibling, err := hex.DecodeString(proof.Siblings[depth])
+		if err != nil || len(sibling) != sha256.Size {
+			return fmt.Errorf("%w: nút anh em %d không hợp lệ", ErrInvalidStateProof, depth)
+		}
+		if stateBit(key, depth) == 0 {
+			hash = merkleNode(hash, sibling)
+		} else {
+			hash = merkleNode(sibling, hash)
+		}
+	}
+	if !bytes.Equal(hash, expected) {
+		return fmt.Errorf("%w: root không khớp", ErrInvalidStateProof)
+	}
+	return nil
+}
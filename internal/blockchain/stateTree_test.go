@@ -0,0 +1,280 @@
+package blockchain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// addressWithPrefix tìm địa chỉ (khác các địa chỉ trong skip) có key bắt đầu bằng các bit trong prefix, ví dụ "010".
+func addressWithPrefix(t *testing.T, prefix string, skip ...string) string {
+	t.Helper()
+search:
+	for i := 0; i < 1<<24; i++ {
+		address := fmt.Sprintf("account-%d", i)
+		for _, other := range skip {
+			if address == other {
+				continue search
+			}
+		}
+		key := stateKey(address)
+		for depth, bit := range prefix {
+			if stateBit(key, depth) != int(bit-'0') {
+				continue search
+			}
+		}
+		return address
+	}
+	t.Fatalf("không tìm được địa chỉ có prefix %s", prefix)
+	return ""
+}
+
+// testStateTree giữ mọi nút đã tạo để có thể cập nhật tiếp và tạo proof.
+type testStateTree struct {
+	t     *testing.T
+	nodes StateNodes
+	root  string
+}
+
+func newTestStateTree(t *testing.T) *testStateTree {
+	return &testStateTree{t: t, nodes: StateNodes{}, root: EmptyStateRoot}
+}
+
+func (s *testStateTree) update(changes StateChanges) string {
+	s.t.Helper()
+	root, created, err := UpdateStateRoot(s.nodes, s.root, changes)
+	if err != nil {
+		s.t.Fatalf("UpdateStateRoot: %v", err)
+	}
+	s.nodes.Merge(created)
+	s.root = root
+	return root
+}
+
+func (s *testStateTree) proof(address string) *StateProof {
+	s.t.Helper()
+	proof, err := BuildStateProof(s.nodes, s.root, address)
+	if err != nil {
+		s.t.Fatalf("BuildStateProof(%s): %v", address, err)
+	}
+	if err := VerifyStateProof(proof, s.root); err != nil {
+		s.t.Fatalf("VerifyStateProof(%s): %v", address, err)
+	}
+	return proof
+}
+
+func TestStateProofNonMembership(t *testing.T) {
+	// a và b cùng nhánh trái, tách nhau ở bit 1; nhánh phải của gốc rỗng
+	a := addressWithPrefix(t, "00")
+	b := addressWithPrefix(t, "01")
+	tree := newTestStateTree(t)
+	tree.update(StateChanges{a: {Balance: 10}, b: {Balance: 20, Nonce: 3}})
+
+	tests := []struct {
+		name      string
+		address   string
+		siblings  int
+		otherLeaf string // địa chỉ của lá khác ở cuối đường đi, rỗng nếu kết thúc ở cây con rỗng
+	}{
+		{"kết thúc ở cây con rỗng", addressWithPrefix(t, "1"), 1, ""},
+		{"kết thúc ở lá của tài khoản khác", addressWithPrefix(t, "00", a), 2, a},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proof := tree.proof(tt.address)
+			if proof.Account != nil {
+				t.Fatalf("proof chứng minh tài khoản %+v, mong đợi không tồn tại", *proof.Account)
+			}
+			if len(proof.Siblings) != tt.siblings {
+				t.Errorf("proof có %d nút anh em, mong đợi %d", len(proof.Siblings), tt.siblings)
+			}
+			wantOther := ""
+			if tt.otherLeaf != "" {
+				wantOther = hex.EncodeToString(stateKey(tt.otherLeaf))
+			}
+			if proof.OtherKey != wantOther {
+				t.Errorf("other_key = %q, mong đợi %q", proof.OtherKey, wantOther)
+			}
+
+			// Không thể biến proof vắng mặt thành proof tồn tại với số dư 0
+			forged := *proof
+			forged.Account = &AccountState{}
+			forged.OtherKey, forged.OtherValueHash = "", ""
+			if err := VerifyStateProof(&forged, tree.root); !errors.Is(err, ErrInvalidStateProof) {
+				t.Errorf("proof tồn tại giả được chấp nhận: %v", err)
+			}
+		})
+	}
+
+	// Lá khác trong proof vắng mặt không được là chính tài khoản đang tồn tại
+	proof := tree.proof(addressWithPrefix(t, "00", a))
+	proof.Address = a
+	if err := VerifyStateProof(proof, tree.root); !errors.Is(err, ErrInvalidStateProof) {
+		t.Errorf("proof vắng mặt của tài khoản đang tồn tại được chấp nhận: %v", err)
+	}
+}
+
+func TestStateTreeKeepsZeroedAccount(t *testing.T) {
+	a, b := addressWithPrefix(t, "0"), addressWithPrefix(t, "1")
+	tree := newTestStateTree(t)
+	tree.update(StateChanges{a: {Balance: 10, Nonce: 1}, b: {Balance: 20}})
+	withoutA := newTestStateTree(t)
+	withoutA.update(StateChanges{b: {Balance: 20}})
+
+	// Tài khoản không bao giờ bị xoá: số dư và nonce về 0 vẫn là một lá, khác với tài khoản chưa tồn tại
+	tree.update(StateChanges{a: {}})
+	if tree.root == withoutA.root {
+		t.Error("tài khoản số dư 0 bị xoá khỏi cây")
+	}
+	proof := tree.proof(a)
+	if proof.Account == nil || *proof.Account != (AccountState{}) {
+		t.Errorf("proof của tài khoản số dư 0 = %+v, mong đợi tài khoản rỗng tồn tại", proof.Account)
+	}
+
+	only := newTestStateTree(t)
+	if only.update(StateChanges{a: {}}) == EmptyStateRoot {
+		t.Error("cây chỉ có một tài khoản số dư 0 có root của cây rỗng")
+	}
+}
+
+func TestStateTreeSplitsLeafWithLongSharedPrefix(t *testing.T) {
+	// Hai key trùng 16 bit đầu và khác nhau ở bit thứ 16
+	const shared = 16
+	prefix := "1011001110001111"
+	a, b := addressWithPrefix(t, prefix+"0"), addressWithPrefix(t, prefix+"1")
+
+	tree := newTestStateTree(t)
+	tree.update(StateChanges{a: {Balance: 1}})
+	if proof := tree.proof(a); len(proof.Siblings) != 0 {
+		t.Fatalf("cây một tài khoản có %d nút anh em, mong đợi lá ở gốc", len(proof.Siblings))
+	}
+	// Proof vắng mặt của b trước khi tách kết thúc ở lá của a ngay tại gốc
+	if proof := tree.proof(b); proof.OtherKey != hex.EncodeToString(stateKey(a)) || len(proof.Siblings) != 0 {
+		t.Errorf("proof vắng mặt của b = %+v, mong đợi lá của a ở gốc", proof)
+	}
+
+	tree.update(StateChanges{b: {Balance: 2}})
+	empty := strings.Repeat("00", 32)
+	for _, address := range []string{a, b} {
+		proof := tree.proof(address)
+		if len(proof.Siblings) != shared+1 {
+			t.Fatalf("proof của %s có %d nút anh em, mong đợi %d", address, len(proof.Siblings), shared+1)
+		}
+		for depth := 0; depth < shared; depth++ {
+			if proof.Siblings[depth] != empty {
+				t.Errorf("nút anh em ở độ sâu %d = %s, mong đợi cây con rỗng", depth, proof.Siblings[depth])
+			}
+		}
+	}
+
+	once := newTestStateTree(t)
+	if once.update(StateChanges{a: {Balance: 1}, b: {Balance: 2}}) != tree.root {
+		t.Error("tách lá khi thêm dần cho root khác với thêm cùng lúc")
+	}
+}
+
+func TestStateRootIndependentOfUpdateOrder(t *testing.T) {
+	var addresses []string
+	for i := 0; i < 12; i++ {
+		addresses = append(addresses, fmt.Sprintf("order-%d", i))
+	}
+	final := StateChanges{}
+	for i, address := range addresses {
+		final[address] = AccountState{Balance: Amount(100 + i), Nonce: uint64(i % 3)}
+	}
+	want := newTestStateTree(t).update(final)
+
+	tests := []struct {
+		name    string
+		batches func() []StateChanges
+	}{
+		{"từng tài khoản theo thứ tự", func() []StateChanges {
+			var batches []StateChanges
+			for _, address := range addresses {
+				batches = append(batches, StateChanges{address: final[address]})
+			}
+			return batches
+		}},
+		{"từng tài khoản theo thứ tự ngược", func() []StateChanges {
+			var batches []StateChanges
+			for i := len(addresses) - 1; i >= 0; i-- {
+				batches = append(batches, StateChanges{addresses[i]: final[addresses[i]]})
+			}
+			return batches
+		}},
+		{"giá trị trung gian bị ghi đè", func() []StateChanges {
+			first, second := StateChanges{}, StateChanges{}
+			for i, address := range addresses {
+				first[address] = AccountState{Balance: Amount(i), Nonce: 7}
+				if i%2 == 0 {
+					second[address] = final[address]
+				}
+			}
+			rest := StateChanges{}
+			for i, address := range addresses {
+				if i%2 == 1 {
+					rest[address] = final[address]
+				}
+			}
+			return []StateChanges{first, second, rest}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := newTestStateTree(t)
+			for _, batch := range tt.batches() {
+				tree.update(batch)
+			}
+			if tree.root != want {
+				t.Errorf("root = %s, mong đợi %s", tree.root, want)
+			}
+		})
+	}
+}
+
+func TestStateProofTampered(t *testing.T) {
+	a, b, c := addressWithPrefix(t, "00"), addressWithPrefix(t, "01"), addressWithPrefix(t, "1")
+	tree := newTestStateTree(t)
+	tree.update(StateChanges{a: {Balance: 10, Nonce: 1}, b: {Balance: 20}, c: {Balance: 30}})
+	member := tree.proof(a)
+	absent := tree.proof(addressWithPrefix(t, "00", a))
+
+	otherLeaf := hex.EncodeToString(stateLeafHash(stateKey(c), accountValueHash(AccountState{Balance: 31})))
+	tests := []struct {
+		name   string
+		proof  *StateProof
+		tamper func(p *StateProof)
+	}{
+		{"đổi số dư", member, func(p *StateProof) { p.Account = &AccountState{Balance: 11, Nonce: 1} }},
+		{"đổi nonce", member, func(p *StateProof) { p.Account = &AccountState{Balance: 10, Nonce: 2} }},
+		{"đổi địa chỉ", member, func(p *StateProof) { p.Address = b }},
+		{"đổi nút anh em", member, func(p *StateProof) { p.Siblings[0] = otherLeaf }},
+		{"đảo nút anh em", member, func(p *StateProof) { p.Siblings[0], p.Siblings[1] = p.Siblings[1], p.Siblings[0] }},
+		{"thiếu nút anh em", member, func(p *StateProof) { p.Siblings = p.Siblings[:1] }},
+		{"thừa nút anh em", member, func(p *StateProof) { p.Siblings = append(p.Siblings, strings.Repeat("00", 32)) }},
+		{"nút anh em không phải hex", member, func(p *StateProof) { p.Siblings[1] = "zz" }},
+		{"vừa tài khoản vừa lá khác", member, func(p *StateProof) {
+			p.OtherKey, p.OtherValueHash = absent.OtherKey, absent.OtherValueHash
+		}},
+		{"đổi giá trị lá khác", absent, func(p *StateProof) {
+			p.OtherValueHash = hex.EncodeToString(accountValueHash(AccountState{Balance: 99}))
+		}},
+		{"lá khác nằm ngoài đường đi", absent, func(p *StateProof) {
+			p.OtherKey = hex.EncodeToString(stateKey(c))
+			p.OtherValueHash = hex.EncodeToString(accountValueHash(AccountState{Balance: 30}))
+		}},
+		{"bỏ lá khác", absent, func(p *StateProof) { p.OtherKey, p.OtherValueHash = "", "" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bad := *tt.proof
+			bad.Siblings = append([]string(nil), tt.proof.Siblings...)
+			tt.tamper(&bad)
+			if err := VerifyStateProof(&bad, tree.root); !errors.Is(err, ErrInvalidStateProof) {
+				t.Errorf("VerifyStateProof = %v, mong đợi %v", err, ErrInvalidStateProof)
+			}
+		})
+	}
+}
@@ -2,18 +2,37 @@ package blockchain
 
 import (
 	"crypto/sha256"
-	"fmt"
+	"encoding/hex"
 )
 
+// AddressLength là độ dài địa chỉ ví: hex của SHA-256 public key (network.GetAddressFromPubKey).
+const AddressLength = 2 * sha256.Size
+
+// ValidAddress cho biết address có đúng dạng địa chỉ ví: AddressLength ký tự hex viết thường.
+func ValidAddress(address string) bool {
+	if len(address) != AddressLength {
+		return false
+	}
+	for _, c := range address {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
 type Transaction struct {
 	Sender    string
 	Receiver  string
-	Amount    float64
+	Amount    Amount
+	Fee       Amount
 	Timestamp int64
 	Signature []byte
+	PublicKey []byte
+	Nonce     uint64
 }
 
-func NewTransactionPtr(sender, receiver string, amount float64, timestamp int64, signature []byte) *Transaction {
+func NewTransactionPtr(sender, receiver string, amount Amount, timestamp int64, signature []byte) *Transaction {
 	return &Transaction{
 		Sender:    sender,
 		Receiver:  receiver,
@@ -23,8 +42,17 @@ func NewTransactionPtr(sender, receiver string, amount float64, timestamp int64,
 	}
 }
 
+// ID là hash giao dịch dạng hex, dùng làm khoá tra cứu và chống trùng.
+func (tx *Transaction) ID() string {
+	return hex.EncodeToString(tx.Hash())
+}
+
+// Cost là tổng số tiền người gửi phải trả (amount + fee).
+func (tx *Transaction) Cost() (Amount, error) {
+	return tx.Amount.Add(tx.Fee)
+}
+
 func (tx *Transaction) Hash() []byte {
-	data := fmt.Sprintf("%s:%s:%f:%d", tx.Sender, tx.Receiver, tx.Amount, tx.Timestamp)
-	hash := sha256.Sum256([]byte(data))
+	hash := sha256.Sum256(tx.Encode())
 	return hash[:]
 }
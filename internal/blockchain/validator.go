@@ -0,0 +1,187 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// RejectReason là mã lý do một block bị từ chối, được trả về cho leader qua gRPC.
+type RejectReason string
+
+const (
+	ReasonBadHash             RejectReason = "BAD_HASH"
+	ReasonBadParent           RejectReason = "BAD_PARENT"
+	ReasonBadHeight           RejectReason = "BAD_HEIGHT"
+	ReasonBadChainID          RejectReason = "BAD_CHAIN_ID"
+	ReasonBadTimestamp        RejectReason = "BAD_TIMESTAMP"
+	ReasonBadMerkleRoot       RejectReason = "BAD_MERKLE_ROOT"
+	ReasonBadStateRoot        RejectReason = "BAD_STATE_ROOT"
+	ReasonBadSignature        RejectReason = "BAD_SIGNATURE"
+	ReasonBadNonce            RejectReason = "BAD_NONCE"
+	ReasonInsufficientBalance RejectReason = "INSUFFICIENT_BALANCE"
+	ReasonDuplicateTx         RejectReason = "DUPLICATE_TX"
+	ReasonBadTransaction      RejectReason = "BAD_TRANSACTION"
+	ReasonBadCertificate      RejectReason = "BAD_CERTIFICATE"
+	ReasonNotLeader           RejectReason = "NOT_LEADER"
+	ReasonConflictingVote     RejectReason = "CONFLICTING_VOTE"
+	ReasonBadProofOfWork      RejectReason = "BAD_POW"
+	ReasonInternal            RejectReason = "INTERNAL"
+)
+
+// ValidationError mô tả lý do block không hợp lệ.
+type ValidationError struct {
+	Reason  RejectReason
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Reason, e.Message)
+}
+
+func reject(reason RejectReason, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// RejectReasonOf trả về mã lý do của lỗi kiểm tra block. Lỗi từ ApplyTransactions được
+// nhận diện theo loại; lỗi khác là ReasonInternal.
+func RejectReasonOf(err error) RejectReason {
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		return verr.Reason
+	}
+	return stateErrorReason(err)
+}
+
+func stateErrorReason(err error) RejectReason {
+	switch {
+	case errors.Is(err, ErrInvalidNonce):
+		return ReasonBadNonce
+	case errors.Is(err, ErrInsufficientBalance):
+		return ReasonInsufficientBalance
+	case errors.Is(err, ErrInvalidAmount), errors.Is(err, ErrAmountOverflow), errors.Is(err, ErrInvalidAddress):
+		return ReasonBadTransaction
+	default:
+		return ReasonInternal
+	}
+}
+
+// SignatureVerifier kiểm tra chữ ký của giao dịch với public key của người gửi.
+type SignatureVerifier func(tx *Transaction) bool
+
+// DefaultMaxFutureDrift là khoảng thời gian tối đa timestamp của block được vượt quá giờ local.
+const DefaultMaxFutureDrift = 15 * time.Second
+
+// Validator kiểm tra đầy đủ một block trước khi bỏ phiếu, commit hoặc lưu khi đồng bộ.
+// State và Nodes phải là trạng thái và cây trạng thái ngay sau block cha.
+type Validator struct {
+	State           StateReader
+	Nodes           StateNodeReader
+	VerifySignature SignatureVerifier
+	MaxFutureDrift  time.Duration
+	Now             func() time.Time
+}
+
+func NewValidator(state StateReader, nodes StateNodeReader, verify SignatureVerifier) *Validator {
+	return &Validator{
+		State:           state,
+		Nodes:           nodes,
+		VerifySignature: verify,
+		MaxFutureDrift:  DefaultMaxFutureDrift,
+		Now:             time.Now,
+	}
+}
+
+// ValidateBlock kiểm tra hash header, liên kết với block cha, timestamp, Merkle root,
+// chữ ký, trùng lặp giao dịch, nonce, số dư và state root sau khi áp dụng block.
+func (v *Validator) ValidateBlock(block, parent *Block) error {
+	if err := v.ValidateBlockStructure(block, parent); err != nil {
+		return err
+	}
+	changes, err := v.applyTransactions(block.Transactions)
+	if err != nil {
+		return err
+	}
+	_, err = CheckStateRoot(v.Nodes, parent, block, changes)
+	return err
+}
+
+// ValidateBlockStructure kiểm tra mọi thứ trừ nonce, số dư và state root, nên không cần State là trạng thái
+// sau block cha. Dùng cho block thuộc nhánh phụ; nonce, số dư và state root được kiểm tra khi đổi nhánh.
+func (v *Validator) ValidateBlockStructure(block, parent *Block) error {
+	if block.CalculateHash() != block.Hash {
+		return reject(ReasonBadHash, "hash header không khớp với nội dung")
+	}
+
+	if err := v.validateHeader(&block.Header, parent); err != nil {
+		return err
+	}
+
+	if CalculateMerkleRoot(block.Transactions) != block.Header.MerkleRoot {
+		return reject(ReasonBadMerkleRoot, "Merkle root không khớp")
+	}
+	if uint64(len(block.Transactions)) != block.Header.TxCount {
+		return reject(ReasonBadMerkleRoot, "header ghi %d giao dịch, block có %d", block.Header.TxCount, len(block.Transactions))
+	}
+
+	return v.verifyTransactions(block.Transactions)
+}
+
+func (v *Validator) validateHeader(header *BlockHeader, parent *Block) error {
+	if parent == nil {
+		return reject(ReasonBadParent, "không có block cha")
+	}
+	if header.PrevHash != parent.Hash {
+		return reject(ReasonBadParent, "prevHash %s không trỏ tới block cha %s", header.PrevHash, parent.Hash)
+	}
+	if header.Height != parent.Header.Height+1 {
+		return reject(ReasonBadHeight, "mong đợi height %d, nhận %d", parent.Header.Height+1, header.Height)
+	}
+	if header.ChainID != parent.Header.ChainID {
+		return reject(ReasonBadChainID, "chain id %q khác %q", header.ChainID, parent.Header.ChainID)
+	}
+
+	if header.Timestamp < parent.Header.Timestamp {
+		return reject(ReasonBadTimestamp, "timestamp %d nhỏ hơn block cha %d", header.Timestamp, parent.Header.Timestamp)
+	}
+	if limit := v.Now().Add(v.MaxFutureDrift).Unix(); header.Timestamp > limit {
+		return reject(ReasonBadTimestamp, "timestamp %d vượt quá thời gian hiện tại", header.Timestamp)
+	}
+	return nil
+}
+
+// ValidateTransactions kiểm tra chữ ký, trùng lặp, nonce và số dư của danh sách giao dịch.
+func (v *Validator) ValidateTransactions(txs []Transaction) error {
+	if err := v.verifyTransactions(txs); err != nil {
+		return err
+	}
+	_, err := v.applyTransactions(txs)
+	return err
+}
+
+// verifyTransactions kiểm tra chữ ký và trùng lặp giao dịch.
+func (v *Validator) verifyTransactions(txs []Transaction) error {
+	seen := make(map[string]bool, len(txs))
+	for i := range txs {
+		tx := &txs[i]
+		id := tx.ID()
+		if seen[id] {
+			return reject(ReasonDuplicateTx, "giao dịch %s xuất hiện nhiều lần", id)
+		}
+		seen[id] = true
+
+		if tx.Sender == "" || !v.VerifySignature(tx) {
+			return reject(ReasonBadSignature, "chữ ký giao dịch #%d (%s) không hợp lệ", i, id)
+		}
+	}
+	return nil
+}
+
+// applyTransactions kiểm tra nonce và số dư với State.
+func (v *Validator) applyTransactions(txs []Transaction) (StateChanges, error) {
+	changes, err := ApplyTransactions(v.State, txs)
+	if err != nil {
+		return nil, reject(stateErrorReason(err), "%v", err)
+	}
+	return changes, nil
+}
@@ -0,0 +1,62 @@
+package blockchain
+
+import "crypto/sha256"
+
+// Vote là phiếu đồng ý của một validator cho block BlockHash ở (Height, Round).
+// Signature là chữ ký ECDSA bằng node key của validator trên SignBytes().
+type Vote struct {
+	Height      uint64 `json:"height"`
+	Round       uint64 `json:"round"`
+	BlockHash   string `json:"block_hash"`
+	ValidatorID string `json:"validator_id"`
+	Signature   []byte `json:"signature"`
+}
+
+// SignBytes trả về SHA-256 của bản mã hoá chuẩn; đây là dữ liệu được ký.
+func (v *Vote) SignBytes() []byte {
+	hash := sha256.Sum256(v.Encode())
+	return hash[:]
+}
+
+// CommitCertificate gom các phiếu đã ký chứng minh block được quorum validator đồng ý.
+type CommitCertificate struct {
+	Height    uint64 `json:"height"`
+	Round     uint64 `json:"round"`
+	BlockHash string `json:"block_hash"`
+	Votes     []Vote `json:"votes"`
+}
+
+// Heartbeat là tin leader gửi định kỳ để giữ vai trò trong nhiệm kỳ Term và báo block cuối của mình.
+// Signature là chữ ký ECDSA bằng node key của LeaderID trên SignBytes(); Timestamp (mili giây Unix)
+// giúp follower bỏ qua heartbeat cũ bị gửi lại.
+type Heartbeat struct {
+	Term      uint64 `json:"term"`
+	LeaderID  string `json:"leader_id"`
+	Height    uint64 `json:"height"`
+	LastHash  string `json:"last_hash"`
+	Timestamp int64  `json:"timestamp"`
+	Signature []byte `json:"signature"`
+}
+
+// SignBytes trả về SHA-256 của bản mã hoá chuẩn; đây là dữ liệu được ký.
+func (h *Heartbeat) SignBytes() []byte {
+	hash := sha256.Sum256(h.Encode())
+	return hash[:]
+}
+
+// VoteRequest là lời xin phiếu của ứng viên CandidateID cho nhiệm kỳ Term, kèm block cuối của ứng viên.
+// Signature là chữ ký ECDSA bằng node key của CandidateID trên SignBytes(), để không ai khác đẩy được
+// nhiệm kỳ của các validator lên thay cho ứng viên.
+type VoteRequest struct {
+	Term        uint64 `json:"term"`
+	CandidateID string `json:"candidate_id"`
+	LastHeight  uint64 `json:"last_height"`
+	LastHash    string `json:"last_hash"`
+	Signature   []byte `json:"signature"`
+}
+
+// SignBytes trả về SHA-256 của bản mã hoá chuẩn; đây là dữ liệu được ký.
+func (r *VoteRequest) SignBytes() []byte {
+	hash := sha256.Sum256(r.Encode())
+	return hash[:]
+}
@@ -0,0 +1,115 @@
+package consensus
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+)
+
+var (
+	ErrMissingCertificate    = errors.New("block không có commit certificate")
+	ErrVoteMismatch          = errors.New("phiếu không khớp block")
+	ErrBadVoteSignature      = errors.New("chữ ký phiếu không hợp lệ")
+	ErrNoQuorum              = errors.New("certificate không đủ quorum")
+	ErrBadHeartbeatSignature = errors.New("chữ ký heartbeat không hợp lệ")
+	ErrBadVoteRequest        = errors.New("chữ ký lời xin phiếu không hợp lệ")
+)
+
+// VerifyVote kiểm tra phiếu thuộc một validator trong tập và chữ ký đúng node key của validator đó.
+func (s *ValidatorSet) VerifyVote(vote *blockchain.Vote) (Validator, error) {
+	v, pubKey, err := s.publicKey(vote.ValidatorID)
+	if err != nil {
+		return Validator{}, err
+	}
+	if !network.VerifyVote(vote, pubKey) {
+		return Validator{}, fmt.Errorf("%w: %s", ErrBadVoteSignature, v.ID)
+	}
+	return v, nil
+}
+
+// VerifyHeartbeat kiểm tra heartbeat được ký bởi validator LeaderID trong tập.
+func (s *ValidatorSet) VerifyHeartbeat(hb *blockchain.Heartbeat) (Validator, error) {
+	v, pubKey, err := s.publicKey(hb.LeaderID)
+	if err != nil {
+		return Validator{}, err
+	}
+	if !network.VerifyHeartbeat(hb, pubKey) {
+		return Validator{}, fmt.Errorf("%w: %s", ErrBadHeartbeatSignature, v.ID)
+	}
+	return v, nil
+}
+
+// VerifyVoteRequest kiểm tra lời xin phiếu được ký bởi validator CandidateID trong tập.
+func (s *ValidatorSet) VerifyVoteRequest(req *blockchain.VoteRequest) (Validator, error) {
+	v, pubKey, err := s.publicKey(req.CandidateID)
+	if err != nil {
+		return Validator{}, err
+	}
+	if !network.VerifyVoteRequest(req, pubKey) {
+		return Validator{}, fmt.Errorf("%w: %s", ErrBadVoteRequest, v.ID)
+	}
+	return v, nil
+}
+
+func (s *ValidatorSet) publicKey(id string) (Validator, *ecdsa.PublicKey, error) {
+	v, ok := s.Get(id)
+	if !ok {
+		return Validator{}, nil, fmt.Errorf("%w: %s", ErrUnknownValidator, id)
+	}
+	raw, err := hex.DecodeString(v.PublicKey)
+	if err != nil {
+		return Validator{}, nil, fmt.Errorf("public key của validator %s không hợp lệ: %w", v.ID, err)
+	}
+	pubKey, err := network.ParsePublicKey(raw)
+	if err != nil {
+		return Validator{}, nil, fmt.Errorf("public key của validator %s không hợp lệ: %w", v.ID, err)
+	}
+	return v, pubKey, nil
+}
+
+// VerifyCertificate kiểm tra certificate thuộc đúng block, mỗi phiếu được ký bởi một validator
+// khác nhau trong tập và tổng voting power vượt quorum.
+func VerifyCertificate(set *ValidatorSet, block *blockchain.Block, cert *blockchain.CommitCertificate) error {
+	if cert == nil {
+		return ErrMissingCertificate
+	}
+	if cert.BlockHash != block.Hash || cert.Height != block.Header.Height {
+		return fmt.Errorf("%w: certificate cho block %s tại height %d", ErrVoteMismatch, cert.BlockHash, cert.Height)
+	}
+
+	tally := NewVoteTally(set, cert.Height, cert.Round, cert.BlockHash)
+	for i := range cert.Votes {
+		if err := tally.Add(cert.Votes[i]); err != nil {
+			return err
+		}
+	}
+	if !tally.HasQuorum() {
+		return fmt.Errorf("%w: %d/%d, cần %d", ErrNoQuorum, tally.Power(), set.TotalPower(), set.QuorumPower())
+	}
+	return nil
+}
+
+// ValidatorSetSource tra cứu tập validator có hiệu lực tại một height (storage.Storage thoả mãn).
+type ValidatorSetSource interface {
+	ValidatorSetAt(height uint64) (*ValidatorSet, error)
+}
+
+// VerifyBlockCertificate kiểm tra certificate của block với tập validator tại height của block.
+// Lỗi trả về là *blockchain.ValidationError với lý do BAD_CERTIFICATE (hoặc INTERNAL).
+func VerifyBlockCertificate(src ValidatorSetSource, block *blockchain.Block, cert *blockchain.CommitCertificate) error {
+	set, err := src.ValidatorSetAt(block.Header.Height)
+	if err != nil {
+		return &blockchain.ValidationError{
+			Reason:  blockchain.ReasonInternal,
+			Message: fmt.Sprintf("không tải được tập validator tại height %d: %v", block.Header.Height, err),
+		}
+	}
+	if err := VerifyCertificate(set, block, cert); err != nil {
+		return &blockchain.ValidationError{Reason: blockchain.ReasonBadCertificate, Message: err.Error()}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+var (
+	ErrNotProposer        = errors.New("node không có quyền tạo block lúc này")
+	ErrVotingNotSupported = errors.New("thuật toán đồng thuận không dùng bỏ phiếu")
+	ErrUnknownEngine      = errors.New("không có thuật toán đồng thuận này")
+)
+
+// Chain là phần lưu trữ chuỗi mà engine cần (storage.Storage thoả mãn).
+type Chain interface {
+	ValidatorSetSource
+	GetLatestBlock() (*blockchain.Block, error)
+	ApplyBlock(block *blockchain.Block) error
+}
+
+// ProposalInfo là thông tin đi kèm một proposal nhận qua gRPC.
+type ProposalInfo struct {
+	LeaderID string
+	Term     uint64
+	Round    uint64
+}
+
+// Engine tách thuật toán đồng thuận khỏi handler và gRPC server. Handler chỉ tạo block từ mempool;
+// engine quyết định ai được tạo block, block được hoàn thiện, bỏ phiếu và commit thế nào.
+type Engine interface {
+	Name() string
+	// CanPropose cho biết node có được tạo block lúc này không.
+	CanPropose() bool
+	// Propose hoàn thiện block vừa tạo trước khi gửi đi (ví dụ: tìm nonce cho PoW).
+	Propose(ctx context.Context, block *blockchain.Block) error
+	// Validate kiểm tra phần đồng thuận của block trước khi lưu (certificate, proof-of-work),
+	// sau khi nội dung block đã được blockchain.Validator kiểm tra.
+	Validate(block, parent *blockchain.Block) error
+	// VerifyHeader là phần của Validate chỉ cần header, hash và certificate (không cần block cha
+	// đã được lưu); dùng khi đồng bộ header trước nội dung block.
+	VerifyHeader(block *blockchain.Block) error
+	// Vote trả về phiếu đã ký cho proposal hợp lệ nhận từ node khác.
+	Vote(block *blockchain.Block, proposal ProposalInfo) (blockchain.Vote, error)
+	// Finalize đưa block tới trạng thái đã commit ở node này và các node khác; trả lỗi nếu không thành.
+	Finalize(ctx context.Context, block *blockchain.Block) error
+	// OnTimeout được gọi khi block ở height không được commit, để engine chuyển sang vòng sau.
+	OnTimeout(height uint64)
+	// Leader trả về validator đang được quyền tạo block, false nếu không có khái niệm leader hoặc chưa biết.
+	Leader() (Validator, bool)
+	// Status là trạng thái của engine, trả về qua HTTP dưới dạng JSON.
+	Status() interface{}
+}
+
+// EngineFromEnv đọc tên thuật toán đồng thuận từ CONSENSUS ("vote" mặc định, hoặc "pow").
+func EngineFromEnv() (string, error) {
+	name := os.Getenv("CONSENSUS")
+	switch name {
+	case "":
+		return "vote", nil
+	case "vote", "pow":
+		return name, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownEngine, name)
+	}
+}
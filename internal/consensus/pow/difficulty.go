@@ -0,0 +1,87 @@
+package pow
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+// maxRetargetStep giới hạn số bit độ khó thay đổi trong một lần điều chỉnh.
+const maxRetargetStep = 2
+
+// BlockLoader tải block theo hash, kể cả block ở nhánh phụ.
+type BlockLoader interface {
+	LoadBlock(hash string) (*blockchain.Block, error)
+}
+
+// NextDifficulty tính độ khó của block nối tiếp parent (xem ExpectedDifficulty).
+func (e *Engine) NextDifficulty(parent *blockchain.Block) (uint32, error) {
+	return ExpectedDifficulty(e.chain, e.cfg, parent)
+}
+
+// ExpectedDifficulty tính độ khó của block nối tiếp parent. Độ khó giữ nguyên trong mỗi chu kỳ
+// RetargetInterval block; ở đầu chu kỳ mới nó được điều chỉnh theo thời gian thực tế của chu kỳ
+// trước so với TargetBlockTime: nhanh gấp đôi thì thêm 1 bit, chậm gấp đôi thì bớt 1 bit (xem retargetStep).
+// Chỉ cần header nên light client cũng dùng được.
+func ExpectedDifficulty(chain BlockLoader, cfg Config, parent *blockchain.Block) (uint32, error) {
+	current := parent.Header.Difficulty
+	if current == 0 {
+		// Block cha là genesis hoặc được tạo bởi engine khác
+		return cfg.InitialDifficulty, nil
+	}
+
+	height := parent.Header.Height + 1
+	interval := cfg.RetargetInterval
+	if interval == 0 || height%interval != 0 || height < interval {
+		return current, nil
+	}
+
+	first := parent
+	for first.Header.Height > height-interval {
+		prev, err := chain.LoadBlock(first.Header.PrevHash)
+		if err != nil {
+			return 0, fmt.Errorf("không tải được block %s để điều chỉnh độ khó: %w", first.Header.PrevHash, err)
+		}
+		first = prev
+	}
+
+	blocks := parent.Header.Height - first.Header.Height
+	if blocks == 0 || first.Header.Difficulty == 0 {
+		// Chu kỳ bắt đầu từ genesis: timestamp của genesis không phản ánh tốc độ đào
+		return current, nil
+	}
+	actual := time.Duration(parent.Header.Timestamp-first.Header.Timestamp) * time.Second
+	if actual <= 0 {
+		actual = time.Second
+	}
+	expected := cfg.TargetBlockTime * time.Duration(blocks)
+
+	step := retargetStep(expected, actual)
+
+	next := int(current) + step
+	if next < 1 {
+		next = 1
+	}
+	if next > 255 {
+		next = 255
+	}
+	return uint32(next), nil
+}
+
+// retargetStep trả về số bit độ khó cần cộng thêm khi chu kỳ kéo dài actual thay vì expected.
+// Chỉ dùng phép so sánh số nguyên để mọi node ra cùng kết quả: bước là k lớn nhất (tối đa
+// maxRetargetStep) sao cho actual <= expected>>k (nhanh ít nhất 2^k lần, tăng k bit) hoặc
+// actual >= expected<<k (chậm ít nhất 2^k lần, giảm k bit). Tỉ lệ nằm giữa hai luỹ thừa của 2
+// được làm tròn về phía 0, ví dụ nhanh gấp 3 lần chỉ tăng 1 bit.
+func retargetStep(expected, actual time.Duration) int {
+	for k := maxRetargetStep; k > 0; k-- {
+		if actual <= expected>>k {
+			return k
+		}
+		if actual >= expected<<k {
+			return -k
+		}
+	}
+	return 0
+}
@@ -0,0 +1,111 @@
+package pow
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+func TestRetargetStep(t *testing.T) {
+	const expected = 40 * time.Second
+
+	tests := []struct {
+		name     string
+		expected time.Duration
+		actual   time.Duration
+		want     int
+	}{
+		{"đúng hạn", expected, expected, 0},
+		{"nhanh gấp đôi", expected, 20 * time.Second, 1},
+		{"nhanh chưa tới gấp đôi", expected, 20*time.Second + 1, 0},
+		{"nhanh gấp ba làm tròn về 1 bit", expected, expected / 3, 1},
+		{"nhanh gấp bốn", expected, 10 * time.Second, 2},
+		{"nhanh chưa tới gấp bốn", expected, 10*time.Second + 1, 1},
+		{"nhanh hơn gấp bốn bị giới hạn", expected, time.Second, maxRetargetStep},
+		{"chậm gấp đôi", expected, 80 * time.Second, -1},
+		{"chậm chưa tới gấp đôi", expected, 80*time.Second - 1, 0},
+		{"chậm gấp bốn", expected, 160 * time.Second, -2},
+		{"chậm chưa tới gấp bốn", expected, 160*time.Second - 1, -1},
+		{"chậm hơn gấp bốn bị giới hạn", expected, time.Hour, -maxRetargetStep},
+		{"expected lẻ, nhanh gấp ba", 3, 1, 1},
+		{"expected lẻ, nhanh chưa tới gấp đôi", 3, 2, 0},
+		{"expected lẻ, chậm gấp đôi", 3, 6, -1},
+		{"expected lẻ, chậm chưa tới gấp đôi", 3, 5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retargetStep(tt.expected, tt.actual); got != tt.want {
+				t.Errorf("retargetStep(%v, %v) = %d, mong đợi %d", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+// testChain là chuỗi block dựng sẵn trong bộ nhớ, block i cách block i-1 đúng spacing giây.
+type testChain map[string]*blockchain.Block
+
+func newTestChain(length uint64, difficulty uint32, spacing int64) (testChain, *blockchain.Block) {
+	chain := testChain{}
+	var prev *blockchain.Block
+	for height := uint64(0); height <= length; height++ {
+		block := &blockchain.Block{Header: blockchain.BlockHeader{
+			Height:    height,
+			Timestamp: 1700000000 + int64(height)*spacing,
+		}}
+		if height > 0 {
+			block.Header.PrevHash = prev.Hash
+			block.Header.Difficulty = difficulty
+		}
+		block.Hash = fmt.Sprintf("block-%d", height)
+		chain[block.Hash] = block
+		prev = block
+	}
+	return chain, prev
+}
+
+func (c testChain) LoadBlock(hash string) (*blockchain.Block, error) {
+	block, ok := c[hash]
+	if !ok {
+		return nil, fmt.Errorf("không có block %s", hash)
+	}
+	return block, nil
+}
+
+func TestExpectedDifficulty(t *testing.T) {
+	cfg := Config{InitialDifficulty: 16, RetargetInterval: 4, TargetBlockTime: 10 * time.Second}
+
+	tests := []struct {
+		name       string
+		length     uint64 // height của block cha
+		difficulty uint32
+		spacing    int64
+		want       uint32
+	}{
+		{"block cha là genesis", 0, 0, 10, 16},
+		{"giữa chu kỳ", 5, 16, 1, 16},
+		{"chu kỳ đầu bắt đầu từ genesis", 3, 16, 1, 16},
+		{"đúng hạn", 7, 16, 10, 16},
+		{"nhanh gấp đôi", 7, 16, 5, 17},
+		{"nhanh hơn một chút so với gấp đôi", 7, 16, 4, 17},
+		{"nhanh gấp mười", 7, 16, 1, 18},
+		{"chậm gấp đôi", 7, 16, 20, 15},
+		{"chậm gấp mười", 7, 16, 100, 14},
+		{"không xuống dưới 1 bit", 7, 1, 100, 1},
+		{"không vượt quá 255 bit", 7, 255, 1, 255},
+		{"timestamp không tăng", 7, 16, 0, 18},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain, parent := newTestChain(tt.length, tt.difficulty, tt.spacing)
+			got, err := ExpectedDifficulty(chain, cfg, parent)
+			if err != nil {
+				t.Fatalf("ExpectedDifficulty: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ExpectedDifficulty = %d, mong đợi %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,253 @@
+// Package pow là engine proof-of-work: mọi node được tạo block, block hợp lệ khi hash header
+// có ít nhất Difficulty bit 0 ở đầu; nonce trong header được thay đổi cho tới khi tìm được hash như vậy.
+// Độ khó được điều chỉnh định kỳ theo thời gian tạo block thực tế.
+package pow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+)
+
+// DefaultDifficulty là độ khó ban đầu mặc định (khoảng 65 nghìn lần băm cho mỗi block).
+const DefaultDifficulty = 16
+
+// ErrStaleParent báo block đang đào không còn nối tiếp block cuối vì đã có block khác được commit.
+var ErrStaleParent = errors.New("đã có block mới từ node khác, dừng đào")
+
+type Config struct {
+	InitialDifficulty uint32        // số bit 0 ở đầu hash cho block đầu tiên sau genesis
+	RetargetInterval  uint64        // điều chỉnh độ khó mỗi chừng này block
+	TargetBlockTime   time.Duration // thời gian mong muốn giữa hai block
+	Threads           int           // số goroutine đào song song
+}
+
+// LoadConfig đọc POW_DIFFICULTY, POW_RETARGET_INTERVAL, POW_TARGET_BLOCK_TIME và POW_THREADS.
+// Ba giá trị đầu phải giống nhau trên mọi node.
+func LoadConfig() Config {
+	cfg := Config{
+		InitialDifficulty: DefaultDifficulty,
+		RetargetInterval:  10,
+		TargetBlockTime:   10 * time.Second,
+		Threads:           runtime.NumCPU(),
+	}
+	if raw := os.Getenv("POW_DIFFICULTY"); raw != "" {
+		if n, err := strconv.ParseUint(raw, 10, 8); err == nil && n > 0 {
+			cfg.InitialDifficulty = uint32(n)
+		} else {
+			log.Printf("POW_DIFFICULTY không hợp lệ (%q), dùng %d", raw, cfg.InitialDifficulty)
+		}
+	}
+	if raw := os.Getenv("POW_RETARGET_INTERVAL"); raw != "" {
+		if n, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			cfg.RetargetInterval = n
+		} else {
+			log.Printf("POW_RETARGET_INTERVAL không hợp lệ (%q), dùng %d", raw, cfg.RetargetInterval)
+		}
+	}
+	if raw := os.Getenv("POW_TARGET_BLOCK_TIME"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.TargetBlockTime = d
+		} else {
+			log.Printf("POW_TARGET_BLOCK_TIME không hợp lệ (%q), dùng %s", raw, cfg.TargetBlockTime)
+		}
+	}
+	if raw := os.Getenv("POW_THREADS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			cfg.Threads = n
+		} else {
+			log.Printf("POW_THREADS không hợp lệ (%q), dùng %d", raw, cfg.Threads)
+		}
+	}
+	return cfg
+}
+
+// Chain là phần lưu trữ mà engine PoW cần; LoadBlock dùng để đi ngược chuỗi khi điều chỉnh độ khó.
+type Chain interface {
+	consensus.Chain
+	BlockLoader
+}
+
+type Engine struct {
+	chain  Chain
+	cfg    Config
+	nodeID string
+}
+
+func New(chain Chain, cfg Config, nodeID string) *Engine {
+	return &Engine{chain: chain, cfg: cfg, nodeID: nodeID}
+}
+
+func (e *Engine) Name() string {
+	return "pow"
+}
+
+func (e *Engine) CanPropose() bool {
+	return true
+}
+
+// tipPollInterval là chu kỳ kiểm tra block cuối trong lúc đào.
+const tipPollInterval = 200 * time.Millisecond
+
+// Propose đặt độ khó cho block rồi đào song song tới khi tìm được nonce. Việc đào dừng lại
+// nếu ctx bị huỷ hoặc có block khác nối vào block cha trước (ErrStaleParent).
+func (e *Engine) Propose(ctx context.Context, block *blockchain.Block) error {
+	parent, err := e.chain.LoadBlock(block.Header.PrevHash)
+	if err != nil {
+		return fmt.Errorf("không tải được block cha: %w", err)
+	}
+	if block.Header.Difficulty, err = e.NextDifficulty(parent); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stale := make(chan struct{})
+	go e.watchTip(ctx, cancel, parent.Hash, stale)
+
+	start := time.Now()
+	nonce, hash, attempts, err := mine(ctx, block.Header, e.cfg.Threads)
+	if err != nil {
+		select {
+		case <-stale:
+			return ErrStaleParent
+		default:
+			return err
+		}
+	}
+
+	block.Header.Nonce = nonce
+	block.Hash = hash
+	log.Printf("Đào được block %d (độ khó %d) sau %d lần thử, %s: %s",
+		block.Header.Height, block.Header.Difficulty, attempts, time.Since(start).Round(time.Millisecond), hash)
+	return nil
+}
+
+// watchTip huỷ việc đào khi block cuối của chuỗi không còn là parentHash.
+func (e *Engine) watchTip(ctx context.Context, cancel context.CancelFunc, parentHash string, stale chan<- struct{}) {
+	ticker := time.NewTicker(tipPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tip, err := e.chain.GetLatestBlock()
+			if err != nil || tip.Hash == parentHash {
+				continue
+			}
+			log.Printf("Nhận block %d (%s) trong lúc đào, dừng đào", tip.Header.Height, tip.Hash)
+			close(stale)
+			cancel()
+			return
+		}
+	}
+}
+
+// Validate kiểm tra độ khó trong header đúng bằng độ khó tính từ block cha và hash đạt độ khó đó.
+func (e *Engine) Validate(block, parent *blockchain.Block) error {
+	expected, err := e.NextDifficulty(parent)
+	if err != nil {
+		return &blockchain.ValidationError{Reason: blockchain.ReasonInternal, Message: err.Error()}
+	}
+	if block.Header.Difficulty != expected {
+		return &blockchain.ValidationError{
+			Reason:  blockchain.ReasonBadProofOfWork,
+			Message: fmt.Sprintf("độ khó %d, mong đợi %d", block.Header.Difficulty, expected),
+		}
+	}
+	return e.VerifyHeader(block)
+}
+
+// VerifyHeader chỉ kiểm tra hash đạt độ khó ghi trong header; độ khó đúng hay không
+// được Validate kiểm tra khi có block cha.
+func (e *Engine) VerifyHeader(block *blockchain.Block) error {
+	if block.Header.Difficulty == 0 || !MeetsDifficulty(block.Hash, block.Header.Difficulty) {
+		return &blockchain.ValidationError{
+			Reason:  blockchain.ReasonBadProofOfWork,
+			Message: fmt.Sprintf("hash %s không đạt độ khó %d", block.Hash, block.Header.Difficulty),
+		}
+	}
+	return nil
+}
+
+func (e *Engine) Vote(block *blockchain.Block, proposal consensus.ProposalInfo) (blockchain.Vote, error) {
+	return blockchain.Vote{}, &blockchain.ValidationError{
+		Reason:  blockchain.ReasonInternal,
+		Message: consensus.ErrVotingNotSupported.Error(),
+	}
+}
+
+// Finalize lưu block ở node này rồi gửi tới các validator khác; mỗi node tự kiểm tra proof-of-work.
+func (e *Engine) Finalize(ctx context.Context, block *blockchain.Block) error {
+	if err := e.chain.ApplyBlock(block); err != nil {
+		return err
+	}
+
+	set, err := e.chain.ValidatorSetAt(block.Header.Height)
+	if err != nil {
+		log.Printf("Không tải được danh sách node để gửi block: %v", err)
+		return nil
+	}
+
+	req := &pb.CommitBlockRequest{Block: utils.ConvertToProtoBlock(block)}
+	var wg sync.WaitGroup
+	for _, peer := range set.Peers(e.nodeID) {
+		wg.Add(1)
+		go func(v consensus.Validator) {
+			defer wg.Done()
+			resp, err := grpcclient.SendCommitBlockToFollower(v.Address, req)
+			if err != nil {
+				log.Printf("Gửi block đến %s thất bại: %v", v.ID, err)
+				return
+			}
+			log.Printf("Node %s nhận block: %s (success: %v, reason: %s)", v.ID, resp.Message, resp.Success, resp.Reason)
+		}(peer)
+	}
+	wg.Wait()
+	return nil
+}
+
+// OnTimeout không cần làm gì: lần tạo block sau sẽ đào lại từ đầu.
+func (e *Engine) OnTimeout(height uint64) {}
+
+func (e *Engine) Leader() (consensus.Validator, bool) {
+	return consensus.Validator{}, false
+}
+
+type Status struct {
+	Engine           string `json:"engine"`
+	NodeID           string `json:"node_id"`
+	Height           uint64 `json:"height"`
+	NextDifficulty   uint32 `json:"next_difficulty"`
+	RetargetInterval uint64 `json:"retarget_interval"`
+	TargetBlockTime  string `json:"target_block_time"`
+	Threads          int    `json:"threads"`
+}
+
+func (e *Engine) Status() interface{} {
+	status := Status{
+		Engine:           e.Name(),
+		NodeID:           e.nodeID,
+		RetargetInterval: e.cfg.RetargetInterval,
+		TargetBlockTime:  e.cfg.TargetBlockTime.String(),
+		Threads:          e.cfg.Threads,
+	}
+	if tip, err := e.chain.GetLatestBlock(); err == nil {
+		status.Height = tip.Header.Height
+		status.NextDifficulty, _ = e.NextDifficulty(tip)
+	}
+	return status
+}
@@ -0,0 +1,94 @@
+package pow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/bits"
+	"sync"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+// mineCheckInterval là số nonce mỗi goroutine thử giữa hai lần kiểm tra ctx.
+const mineCheckInterval = 1 << 12
+
+// mine tìm nonce cho header trên threads goroutine, mỗi goroutine thử các nonce cách nhau threads.
+// Trả về nonce và hash tìm được, hoặc lỗi của ctx nếu bị huỷ trước.
+func mine(ctx context.Context, header blockchain.BlockHeader, threads int) (uint64, string, uint64, error) {
+	if threads < 1 {
+		threads = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		nonce uint64
+		hash  string
+	}
+	found := make(chan result, 1)
+
+	var (
+		wg       sync.WaitGroup
+		attempts uint64
+		countMu  sync.Mutex
+	)
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(start uint64) {
+			defer wg.Done()
+			h := header
+			var tried uint64
+			defer func() {
+				countMu.Lock()
+				attempts += tried
+				countMu.Unlock()
+			}()
+
+			for nonce := start; ; nonce += uint64(threads) {
+				if tried%mineCheckInterval == 0 && ctx.Err() != nil {
+					return
+				}
+				tried++
+				h.Nonce = nonce
+				sum := sha256.Sum256(h.Encode())
+				if leadingZeroBits(sum[:]) >= int(h.Difficulty) {
+					select {
+					case found <- result{nonce: nonce, hash: hex.EncodeToString(sum[:])}:
+					default:
+					}
+					cancel()
+					return
+				}
+			}
+		}(uint64(i))
+	}
+	wg.Wait()
+
+	select {
+	case r := <-found:
+		return r.nonce, r.hash, attempts, nil
+	default:
+		return 0, "", attempts, ctx.Err()
+	}
+}
+
+// MeetsDifficulty cho biết hash (hex) có ít nhất difficulty bit 0 ở đầu.
+func MeetsDifficulty(hash string, difficulty uint32) bool {
+	raw, err := hex.DecodeString(hash)
+	if err != nil {
+		return false
+	}
+	return leadingZeroBits(raw) >= int(difficulty)
+}
+
+func leadingZeroBits(data []byte) int {
+	zeros := 0
+	for _, b := range data {
+		if b != 0 {
+			return zeros + bits.LeadingZeros8(b)
+		}
+		zeros += 8
+	}
+	return zeros
+}
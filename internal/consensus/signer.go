@@ -0,0 +1,101 @@
+package consensus
+
+import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+)
+
+var ErrNoNodeKey = errors.New("node chưa được cấu hình node key")
+
+// Signer ký phiếu bầu, heartbeat và lời xin phiếu thay cho validator ID bằng node key của node.
+type Signer struct {
+	ID  string
+	key *ecdsa.PrivateKey
+}
+
+// NewSigner tạo Signer từ private key dạng hex; keyHex rỗng cho phép node chạy nhưng không bỏ phiếu
+// hay làm leader được.
+func NewSigner(id, keyHex string) (*Signer, error) {
+	s := &Signer{ID: id}
+	if keyHex == "" {
+		return s, nil
+	}
+	raw, err := hex.DecodeString(keyHex)
+	if err != nil {
+		return nil, fmt.Errorf("node key phải là chuỗi hex: %w", err)
+	}
+	if s.key, err = network.ParsePrivateKey(raw); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// PublicKey trả về public key (hex, X||Y) của node key, rỗng nếu chưa có key.
+func (s *Signer) PublicKey() string {
+	if s.key == nil {
+		return ""
+	}
+	return hex.EncodeToString(network.MarshalPublicKey(&s.key.PublicKey))
+}
+
+func (s *Signer) SignVote(height, round uint64, blockHash string) (blockchain.Vote, error) {
+	if s.key == nil {
+		return blockchain.Vote{}, ErrNoNodeKey
+	}
+	vote := blockchain.Vote{
+		Height:      height,
+		Round:       round,
+		BlockHash:   blockHash,
+		ValidatorID: s.ID,
+	}
+	if err := network.SignVote(&vote, s.key); err != nil {
+		return blockchain.Vote{}, err
+	}
+	return vote, nil
+}
+
+// CanSign cho biết node có node key để ký không.
+func (s *Signer) CanSign() bool {
+	return s.key != nil
+}
+
+// SignHeartbeat ký heartbeat của leader cho nhiệm kỳ term, kèm block cuối và thời điểm hiện tại.
+func (s *Signer) SignHeartbeat(term, height uint64, lastHash string) (blockchain.Heartbeat, error) {
+	if s.key == nil {
+		return blockchain.Heartbeat{}, ErrNoNodeKey
+	}
+	hb := blockchain.Heartbeat{
+		Term:      term,
+		LeaderID:  s.ID,
+		Height:    height,
+		LastHash:  lastHash,
+		Timestamp: time.Now().UnixMilli(),
+	}
+	if err := network.SignHeartbeat(&hb, s.key); err != nil {
+		return blockchain.Heartbeat{}, err
+	}
+	return hb, nil
+}
+
+// SignVoteRequest ký lời xin phiếu của node cho nhiệm kỳ term, kèm block cuối của node.
+func (s *Signer) SignVoteRequest(term, lastHeight uint64, lastHash string) (blockchain.VoteRequest, error) {
+	if s.key == nil {
+		return blockchain.VoteRequest{}, ErrNoNodeKey
+	}
+	req := blockchain.VoteRequest{
+		Term:        term,
+		CandidateID: s.ID,
+		LastHeight:  lastHeight,
+		LastHash:    lastHash,
+	}
+	if err := network.SignVoteRequest(&req, s.key); err != nil {
+		return blockchain.VoteRequest{}, err
+	}
+	return req, nil
+}
@@ -0,0 +1,278 @@
+// Package consensus chứa tập validator, cách tính quorum và kiểm phiếu cho mỗi block.
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+var (
+	ErrUnknownValidator = errors.New("validator không thuộc tập hiện tại")
+	ErrDuplicateVote    = errors.New("validator đã bỏ phiếu cho block này")
+)
+
+// Quorum là tỉ lệ voting power cần vượt qua (lớn hơn hẳn Num/Den) để một block được commit.
+type Quorum struct {
+	Num uint64 `json:"num"`
+	Den uint64 `json:"den"`
+}
+
+// DefaultQuorum yêu cầu hơn 2/3 tổng voting power.
+var DefaultQuorum = Quorum{Num: 2, Den: 3}
+
+// ParseQuorum đọc tỉ lệ dạng "2/3"; chuỗi rỗng trả về DefaultQuorum.
+func ParseQuorum(raw string) (Quorum, error) {
+	if raw == "" {
+		return DefaultQuorum, nil
+	}
+	num, den, ok := strings.Cut(raw, "/")
+	if !ok {
+		return Quorum{}, fmt.Errorf("quorum %q phải có dạng a/b", raw)
+	}
+	var q Quorum
+	var err error
+	if q.Num, err = strconv.ParseUint(strings.TrimSpace(num), 10, 64); err != nil {
+		return Quorum{}, fmt.Errorf("quorum %q không hợp lệ: %w", raw, err)
+	}
+	if q.Den, err = strconv.ParseUint(strings.TrimSpace(den), 10, 64); err != nil {
+		return Quorum{}, fmt.Errorf("quorum %q không hợp lệ: %w", raw, err)
+	}
+	return q, q.Validate()
+}
+
+func (q Quorum) Validate() error {
+	if q.Den == 0 || q.Num >= q.Den {
+		return fmt.Errorf("quorum %s phải nằm trong [0, 1)", q)
+	}
+	return nil
+}
+
+func (q Quorum) String() string {
+	return fmt.Sprintf("%d/%d", q.Num, q.Den)
+}
+
+// Validator là một node có quyền bỏ phiếu. Address là địa chỉ gRPC, APIAddress là địa chỉ HTTP
+// để chuyển client tới leader. PublicKey được mã hoá hex (X||Y).
+type Validator struct {
+	ID         string `json:"id"`
+	Address    string `json:"address"`
+	APIAddress string `json:"api_address,omitempty"`
+	PublicKey  string `json:"public_key,omitempty"`
+	Power      uint64 `json:"power"`
+}
+
+// ValidatorSet là tập validator có hiệu lực từ block Height trở đi,
+// cho tới khi một tập khác có height lớn hơn được lưu.
+type ValidatorSet struct {
+	Height     uint64      `json:"height"`
+	Validators []Validator `json:"validators"`
+	Quorum     Quorum      `json:"quorum"`
+}
+
+func NewValidatorSet(height uint64, validators []Validator, quorum Quorum) (*ValidatorSet, error) {
+	set := &ValidatorSet{Height: height, Validators: validators, Quorum: quorum}
+	if err := set.Validate(); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// NewValidatorSetFromGenesis tạo tập validator có hiệu lực từ genesis block.
+func NewValidatorSetFromGenesis(g *blockchain.Genesis) (*ValidatorSet, error) {
+	return newGenesisValidatorSet(0, g.Validators, g.Quorum)
+}
+
+func newGenesisValidatorSet(height uint64, genesisValidators []blockchain.GenesisValidator, rawQuorum string) (*ValidatorSet, error) {
+	quorum, err := ParseQuorum(rawQuorum)
+	if err != nil {
+		return nil, err
+	}
+	validators := make([]Validator, 0, len(genesisValidators))
+	for _, v := range genesisValidators {
+		validators = append(validators, Validator{
+			ID:         v.ID,
+			Address:    v.Address,
+			APIAddress: v.APIAddress,
+			PublicKey:  v.PublicKey,
+			Power:      v.Power,
+		})
+	}
+	return NewValidatorSet(height, validators, quorum)
+}
+
+// ValidatorSets là các tập validator theo height kích hoạt tăng dần, bắt đầu từ height 0.
+type ValidatorSets []*ValidatorSet
+
+// NewValidatorSetsFromGenesis tạo tập validator của genesis cùng các tập trong validator_updates.
+func NewValidatorSetsFromGenesis(g *blockchain.Genesis) (ValidatorSets, error) {
+	first, err := NewValidatorSetFromGenesis(g)
+	if err != nil {
+		return nil, err
+	}
+	sets := ValidatorSets{first}
+	for _, update := range g.ValidatorUpdates {
+		set, err := newGenesisValidatorSet(update.Height, update.Validators, update.Quorum)
+		if err != nil {
+			return nil, fmt.Errorf("tập validator tại height %d: %w", update.Height, err)
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// ValidatorSetAt trả về tập có Height lớn nhất <= height.
+func (sets ValidatorSets) ValidatorSetAt(height uint64) (*ValidatorSet, error) {
+	for i := len(sets) - 1; i >= 0; i-- {
+		if sets[i].Height <= height {
+			return sets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("không có tập validator tại height %d", height)
+}
+
+func (s *ValidatorSet) Validate() error {
+	if len(s.Validators) == 0 {
+		return errors.New("tập validator rỗng")
+	}
+	ids := make(map[string]bool, len(s.Validators))
+	var total uint64
+	for _, v := range s.Validators {
+		if v.ID == "" || ids[v.ID] {
+			return fmt.Errorf("validator trùng hoặc thiếu id: %q", v.ID)
+		}
+		ids[v.ID] = true
+		if v.PublicKey == "" {
+			return fmt.Errorf("validator %s thiếu public_key", v.ID)
+		}
+		if v.Power == 0 {
+			return fmt.Errorf("validator %s có voting power bằng 0", v.ID)
+		}
+		if total+v.Power < total {
+			return errors.New("tổng voting power bị tràn số")
+		}
+		total += v.Power
+	}
+	return s.Quorum.Validate()
+}
+
+func (s *ValidatorSet) TotalPower() uint64 {
+	var total uint64
+	for _, v := range s.Validators {
+		total += v.Power
+	}
+	return total
+}
+
+// QuorumPower là voting power nhỏ nhất đủ để commit block.
+func (s *ValidatorSet) QuorumPower() uint64 {
+	total := s.TotalPower()
+	// floor(total*Num/Den) + 1, tính theo từng phần để tránh tràn số
+	need := total/s.Quorum.Den*s.Quorum.Num + total%s.Quorum.Den*s.Quorum.Num/s.Quorum.Den
+	return need + 1
+}
+
+func (s *ValidatorSet) HasQuorum(power uint64) bool {
+	return power >= s.QuorumPower()
+}
+
+// HasMajority cho biết power có vượt quá một nửa tổng voting power (dùng khi bầu leader).
+func (s *ValidatorSet) HasMajority(power uint64) bool {
+	return power > s.TotalPower()/2
+}
+
+func (s *ValidatorSet) Get(id string) (Validator, bool) {
+	for _, v := range s.Validators {
+		if v.ID == id {
+			return v, true
+		}
+	}
+	return Validator{}, false
+}
+
+// SameVoters cho biết hai tập có cùng height, quorum và cùng danh sách validator (id, public key, voting power)
+// theo cùng thứ tự, tức chấp nhận cùng các phiếu; địa chỉ của validator không được so sánh.
+func (s *ValidatorSet) SameVoters(other *ValidatorSet) bool {
+	if s.Height != other.Height || s.Quorum != other.Quorum || len(s.Validators) != len(other.Validators) {
+		return false
+	}
+	for i, v := range s.Validators {
+		o := other.Validators[i]
+		if v.ID != o.ID || v.PublicKey != o.PublicKey || v.Power != o.Power {
+			return false
+		}
+	}
+	return true
+}
+
+// Peers trả về các validator khác selfID, theo thứ tự trong tập.
+func (s *ValidatorSet) Peers(selfID string) []Validator {
+	peers := make([]Validator, 0, len(s.Validators))
+	for _, v := range s.Validators {
+		if v.ID != selfID {
+			peers = append(peers, v)
+		}
+	}
+	return peers
+}
+
+// VoteTally đếm các phiếu đã ký cho một block trong một vòng bỏ phiếu.
+// Không an toàn khi dùng đồng thời; người gọi tự khoá.
+type VoteTally struct {
+	Height    uint64
+	Round     uint64
+	BlockHash string
+	set       *ValidatorSet
+	votes     []blockchain.Vote
+	voters    map[string]bool
+	power     uint64
+}
+
+func NewVoteTally(set *ValidatorSet, height, round uint64, blockHash string) *VoteTally {
+	return &VoteTally{
+		Height:    height,
+		Round:     round,
+		BlockHash: blockHash,
+		set:       set,
+		voters:    make(map[string]bool),
+	}
+}
+
+// Add kiểm tra và ghi nhận phiếu; mỗi validator chỉ được tính một lần.
+func (t *VoteTally) Add(vote blockchain.Vote) error {
+	if vote.Height != t.Height || vote.Round != t.Round || vote.BlockHash != t.BlockHash {
+		return fmt.Errorf("%w: phiếu của %s cho %s (height %d, round %d)", ErrVoteMismatch, vote.ValidatorID, vote.BlockHash, vote.Height, vote.Round)
+	}
+	if t.voters[vote.ValidatorID] {
+		return fmt.Errorf("%w: %s", ErrDuplicateVote, vote.ValidatorID)
+	}
+	v, err := t.set.VerifyVote(&vote)
+	if err != nil {
+		return err
+	}
+	t.voters[vote.ValidatorID] = true
+	t.votes = append(t.votes, vote)
+	t.power += v.Power
+	return nil
+}
+
+func (t *VoteTally) Power() uint64 {
+	return t.power
+}
+
+func (t *VoteTally) HasQuorum() bool {
+	return t.set.HasQuorum(t.power)
+}
+
+// Certificate gom các phiếu đã nhận thành commit certificate.
+func (t *VoteTally) Certificate() *blockchain.CommitCertificate {
+	return &blockchain.CommitCertificate{
+		Height:    t.Height,
+		Round:     t.Round,
+		BlockHash: t.BlockHash,
+		Votes:     append([]blockchain.Vote(nil), t.votes...),
+	}
+}
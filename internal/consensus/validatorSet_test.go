@@ -0,0 +1,185 @@
+package consensus
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+)
+
+// newTestValidators tạo n validator có voting power 1 cùng Signer của từng validator.
+func newTestValidators(t *testing.T, n int) ([]Validator, []*Signer) {
+	t.Helper()
+	validators := make([]Validator, 0, n)
+	signers := make([]*Signer, 0, n)
+	for i := 0; i < n; i++ {
+		key, err := network.GenerateKeyPair()
+		if err != nil {
+			t.Fatal(err)
+		}
+		signer, err := NewSigner(fmt.Sprintf("v%d", i), hex.EncodeToString(network.MarshalPrivateKey(key)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		validators = append(validators, Validator{ID: signer.ID, PublicKey: signer.PublicKey(), Power: 1})
+		signers = append(signers, signer)
+	}
+	return validators, signers
+}
+
+func testPowerSet(t *testing.T, total uint64, quorum string) *ValidatorSet {
+	t.Helper()
+	q, err := ParseQuorum(quorum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := NewValidatorSet(0, []Validator{{ID: "v", PublicKey: "0a0b", Power: total}}, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+// Quorum yêu cầu lớn hơn hẳn tỉ lệ: đúng 2/3 hay đúng 1/2 tổng voting power là chưa đủ.
+func TestQuorumPowerBoundary(t *testing.T) {
+	tests := []struct {
+		total  uint64
+		quorum string
+		need   uint64
+	}{
+		{3, "2/3", 3},
+		{4, "2/3", 3},
+		{100, "2/3", 67},
+		{3, "1/2", 2},
+		{4, "1/2", 3},
+		{100, "1/2", 51},
+		{1, "2/3", 1},
+		{3, "0/1", 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d %s", tt.total, tt.quorum), func(t *testing.T) {
+			set := testPowerSet(t, tt.total, tt.quorum)
+			if got := set.QuorumPower(); got != tt.need {
+				t.Errorf("QuorumPower = %d, mong đợi %d", got, tt.need)
+			}
+			if set.HasQuorum(tt.need - 1) {
+				t.Errorf("HasQuorum(%d) = true, mong đợi false", tt.need-1)
+			}
+			if !set.HasQuorum(tt.need) {
+				t.Errorf("HasQuorum(%d) = false, mong đợi true", tt.need)
+			}
+		})
+	}
+}
+
+func TestQuorumPowerLargeTotal(t *testing.T) {
+	// total*Num tràn uint64 nếu nhân trước khi chia
+	const total = 1<<64 - 1
+	set := testPowerSet(t, total, "2/3")
+	want := uint64(total/3*2 + 1)
+	if got := set.QuorumPower(); got != want {
+		t.Errorf("QuorumPower = %d, mong đợi %d", got, want)
+	}
+}
+
+// Bầu leader cần quá nửa tổng voting power.
+func TestHasMajorityBoundary(t *testing.T) {
+	tests := []struct {
+		total uint64
+		need  uint64
+	}{
+		{1, 1},
+		{3, 2},
+		{4, 3},
+		{100, 51},
+	}
+	for _, tt := range tests {
+		set := testPowerSet(t, tt.total, "")
+		if set.HasMajority(tt.need-1) || !set.HasMajority(tt.need) {
+			t.Errorf("tổng %d: HasMajority(%d) = %v, HasMajority(%d) = %v; mong đợi false, true",
+				tt.total, tt.need-1, set.HasMajority(tt.need-1), tt.need, set.HasMajority(tt.need))
+		}
+	}
+}
+
+func TestVoteTallyReachesQuorumAtBoundary(t *testing.T) {
+	for _, total := range []int{3, 4, 100} {
+		validators, signers := newTestValidators(t, total)
+		for _, quorum := range []string{"2/3", "1/2"} {
+			t.Run(fmt.Sprintf("%d %s", total, quorum), func(t *testing.T) {
+				q, err := ParseQuorum(quorum)
+				if err != nil {
+					t.Fatal(err)
+				}
+				set, err := NewValidatorSet(0, validators, q)
+				if err != nil {
+					t.Fatal(err)
+				}
+				need := set.QuorumPower()
+
+				tally := NewVoteTally(set, 7, 1, "block")
+				for i, signer := range signers {
+					vote, err := signer.SignVote(7, 1, "block")
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := tally.Add(vote); err != nil {
+						t.Fatalf("phiếu của %s: %v", signer.ID, err)
+					}
+					if got, want := tally.HasQuorum(), uint64(i+1) >= need; got != want {
+						t.Fatalf("sau %d/%d phiếu HasQuorum = %v, mong đợi %v", i+1, total, got, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestVoteTallyCountsValidatorOnce(t *testing.T) {
+	validators, signers := newTestValidators(t, 4)
+	set, err := NewValidatorSet(0, validators, DefaultQuorum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tally := NewVoteTally(set, 7, 0, "block")
+	vote, err := signers[0].SignVote(7, 0, "block")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Chữ ký ECDSA mới cho cùng phiếu vẫn là phiếu của cùng validator
+	resigned, err := signers[0].SignVote(7, 0, "block")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tally.Add(vote); err != nil {
+		t.Fatal(err)
+	}
+	if err := tally.Add(vote); !errors.Is(err, ErrDuplicateVote) {
+		t.Errorf("gửi lại phiếu: %v, mong đợi %v", err, ErrDuplicateVote)
+	}
+	if err := tally.Add(resigned); !errors.Is(err, ErrDuplicateVote) {
+		t.Errorf("ký lại phiếu: %v, mong đợi %v", err, ErrDuplicateVote)
+	}
+	if tally.Power() != 1 || len(tally.Certificate().Votes) != 1 {
+		t.Errorf("phiếu trùng được tính: power %d, %d phiếu", tally.Power(), len(tally.Certificate().Votes))
+	}
+
+	// Certificate đủ số phiếu nhờ phiếu trùng không được chấp nhận
+	second, err := signers[1].SignVote(7, 0, "block")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tally.Add(second); err != nil {
+		t.Fatal(err)
+	}
+	cert := tally.Certificate()
+	cert.Votes = append(cert.Votes, resigned)
+	block := &blockchain.Block{Header: blockchain.BlockHeader{Height: 7}, Hash: "block"}
+	if err := VerifyCertificate(set, block, cert); !errors.Is(err, ErrDuplicateVote) {
+		t.Errorf("VerifyCertificate với phiếu trùng = %v, mong đợi %v", err, ErrDuplicateVote)
+	}
+}
@@ -0,0 +1,282 @@
+// Package vote là engine đồng thuận một leader: leader được bầu (xem package election) đề xuất block,
+// các validator ký phiếu và block được commit khi voting power đồng ý vượt quorum.
+package vote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/election"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+)
+
+var (
+	ErrConflictingVote = errors.New("đã ký phiếu cho block khác ở cùng height và round")
+	ErrStaleVote       = errors.New("đã ký phiếu ở height hoặc round cao hơn")
+)
+
+// Chain là phần lưu trữ mà engine cần (storage.Storage thoả mãn): ngoài chuỗi còn có phiếu đã ký gần nhất.
+type Chain interface {
+	consensus.Chain
+	LoadLastVote() (blockchain.Vote, bool, error)
+	SaveLastVote(vote blockchain.Vote) error
+}
+
+type Engine struct {
+	chain    Chain
+	signer   *consensus.Signer
+	election *election.Election
+
+	// signMu giữ cho việc kiểm tra, ký và lưu phiếu gần nhất diễn ra liền nhau
+	signMu sync.Mutex
+
+	mu sync.Mutex
+	// tallies giữ phiếu của từng vòng bỏ phiếu, theo hash block
+	tallies map[string]*consensus.VoteTally
+	// round là vòng bỏ phiếu hiện tại ở roundHeight; tăng khi block không được commit
+	roundHeight uint64
+	round       uint64
+}
+
+func New(chain Chain, signer *consensus.Signer, elect *election.Election) *Engine {
+	return &Engine{
+		chain:    chain,
+		signer:   signer,
+		election: elect,
+		tallies:  make(map[string]*consensus.VoteTally),
+	}
+}
+
+func (e *Engine) Name() string {
+	return "vote"
+}
+
+func (e *Engine) CanPropose() bool {
+	return e.election.IsLeader()
+}
+
+// Propose không cần làm gì: phiếu của leader được ký trong Finalize cùng các phiếu khác.
+func (e *Engine) Propose(ctx context.Context, block *blockchain.Block) error {
+	return nil
+}
+
+func (e *Engine) Validate(block, parent *blockchain.Block) error {
+	return e.VerifyHeader(block)
+}
+
+// VerifyHeader kiểm tra commit certificate của block; certificate chỉ ký trên height và hash.
+func (e *Engine) VerifyHeader(block *blockchain.Block) error {
+	return consensus.VerifyBlockCertificate(e.chain, block, block.Certificate)
+}
+
+// Vote chỉ ký phiếu cho proposal của leader hợp lệ trong nhiệm kỳ hiện tại.
+func (e *Engine) Vote(block *blockchain.Block, proposal consensus.ProposalInfo) (blockchain.Vote, error) {
+	if err := e.election.AcceptLeader(proposal.Term, proposal.LeaderID); err != nil {
+		return blockchain.Vote{}, &blockchain.ValidationError{Reason: blockchain.ReasonNotLeader, Message: err.Error()}
+	}
+	vote, err := e.signVote(block.Header.Height, proposal.Round, block.Hash)
+	if errors.Is(err, ErrConflictingVote) || errors.Is(err, ErrStaleVote) {
+		return blockchain.Vote{}, &blockchain.ValidationError{Reason: blockchain.ReasonConflictingVote, Message: err.Error()}
+	}
+	if err != nil {
+		return blockchain.Vote{}, &blockchain.ValidationError{Reason: blockchain.ReasonInternal, Message: "không ký được phiếu: " + err.Error()}
+	}
+	return vote, nil
+}
+
+// signVote chỉ ký nếu phiếu không mâu thuẫn với phiếu đã ký gần nhất: không ký block khác ở cùng
+// (height, round), không ký ở height hay round thấp hơn. Phiếu được lưu trước khi trả về, nên leader
+// không gom được hai certificate cho hai block khác nhau ở cùng height và round, kể cả khi node khởi động lại.
+func (e *Engine) signVote(height, round uint64, blockHash string) (blockchain.Vote, error) {
+	e.signMu.Lock()
+	defer e.signMu.Unlock()
+
+	last, ok, err := e.chain.LoadLastVote()
+	if err != nil {
+		return blockchain.Vote{}, err
+	}
+	if ok {
+		switch {
+		case height < last.Height || height == last.Height && round < last.Round:
+			return blockchain.Vote{}, fmt.Errorf("%w: height %d round %d", ErrStaleVote, last.Height, last.Round)
+		case height == last.Height && round == last.Round && blockHash != last.BlockHash:
+			return blockchain.Vote{}, fmt.Errorf("%w: block %s", ErrConflictingVote, last.BlockHash)
+		}
+	}
+
+	vote, err := e.signer.SignVote(height, round, blockHash)
+	if err != nil {
+		return blockchain.Vote{}, err
+	}
+	if err := e.chain.SaveLastVote(vote); err != nil {
+		return blockchain.Vote{}, err
+	}
+	return vote, nil
+}
+
+// Finalize gửi proposal tới các validator khác và gom các phiếu đã ký. Nếu tổng voting power
+// (kể cả phiếu của leader) vượt quorum thì gắn commit certificate vào block, commit ở leader
+// rồi gửi commit kèm certificate tới các validator. Trả lỗi nếu block không được commit.
+func (e *Engine) Finalize(ctx context.Context, b *blockchain.Block) error {
+	set, err := e.chain.ValidatorSetAt(b.Header.Height)
+	if err != nil {
+		return fmt.Errorf("không tải được tập validator: %w", err)
+	}
+
+	round := e.startRound(b, set)
+	defer e.endRound(b.Hash)
+
+	// Leader tự bỏ phiếu cho block do mình tạo nếu là validator
+	if _, ok := set.Get(e.signer.ID); ok {
+		vote, err := e.signVote(b.Header.Height, round, b.Hash)
+		if err != nil {
+			log.Printf("Leader không tự bỏ phiếu được: %v", err)
+		} else {
+			e.addVote(vote)
+		}
+	}
+
+	protoBlock := utils.ConvertToProtoBlock(b)
+
+	req := &pb.ProposalRequest{
+		Block:    protoBlock,
+		LeaderID: e.signer.ID,
+		Round:    round,
+		Term:     e.election.Term(),
+	}
+	peers := set.Peers(e.signer.ID)
+	var wg sync.WaitGroup
+	for _, peer := range peers {
+		wg.Add(1)
+
+		go func(v consensus.Validator) {
+			defer wg.Done()
+			resp, err := grpcclient.SendProposalToFollower(v.Address, req)
+			if err != nil {
+				log.Printf("Gửi proposal đến %s (%s) thất bại: %v\n", v.ID, v.Address, err)
+				return
+			}
+			log.Printf("Validator %s phản hồi: %s (accepted: %v, reason: %s)\n", v.ID, resp.Message, resp.Accepted, resp.Reason)
+			if !resp.Accepted || resp.Vote == nil {
+				return
+			}
+			vote := utils.ConvertFromProtoVote(resp.Vote)
+			if vote.ValidatorID != v.ID {
+				log.Printf("Bỏ qua phiếu của %s gửi từ %s", vote.ValidatorID, v.ID)
+				return
+			}
+			e.addVote(vote)
+		}(peer)
+	}
+
+	wg.Wait()
+
+	cert, power := e.roundResult(b.Hash)
+	log.Printf("Voting power đồng thuận: %d/%d (cần %d, quorum %s)\n", power, set.TotalPower(), set.QuorumPower(), set.Quorum)
+
+	if cert == nil {
+		log.Println("Không đủ phiếu, không gửi commit")
+		return errors.New("không đủ phiếu đồng thuận")
+	}
+
+	log.Println("Đủ phiếu, tiến hành gửi commit đến các validator")
+
+	b.Certificate = cert
+	commitReq := &pb.CommitBlockRequest{
+		Block:       protoBlock,
+		Certificate: utils.ConvertToProtoCertificate(cert),
+	}
+	if err := e.chain.ApplyBlock(b); err != nil {
+		log.Printf("áp dụng block ở leader thất bại: %v", err)
+		return fmt.Errorf("áp dụng block ở leader thất bại: %w", err)
+	}
+	for _, peer := range peers {
+		wg.Add(1)
+		go func(v consensus.Validator) {
+			defer wg.Done()
+			resp, err := grpcclient.SendCommitBlockToFollower(v.Address, commitReq)
+			if err != nil {
+				log.Printf("Gửi commit đến %s thất bại: %v\n", v.ID, err)
+				return
+			}
+			log.Printf("Commit xác nhận từ %s: %s (success: %v)\n", v.ID, resp.Message, resp.Success)
+		}(peer)
+	}
+	wg.Wait()
+	return nil
+}
+
+// OnTimeout chuyển sang vòng bỏ phiếu tiếp theo ở height; phiếu của các vòng trước không được tính.
+func (e *Engine) OnTimeout(height uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if height == e.roundHeight {
+		e.round++
+	} else {
+		e.roundHeight = height
+		e.round = 1
+	}
+}
+
+func (e *Engine) Leader() (consensus.Validator, bool) {
+	return e.election.Leader()
+}
+
+func (e *Engine) Status() interface{} {
+	return e.election.Status()
+}
+
+// startRound mở vòng bỏ phiếu cho block và trả về số round hiện tại ở height của block.
+func (e *Engine) startRound(b *blockchain.Block, set *consensus.ValidatorSet) uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if b.Header.Height != e.roundHeight {
+		e.roundHeight = b.Header.Height
+		e.round = 0
+	}
+	e.tallies[b.Hash] = consensus.NewVoteTally(set, b.Header.Height, e.round, b.Hash)
+	return e.round
+}
+
+func (e *Engine) endRound(blockHash string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	delete(e.tallies, blockHash)
+}
+
+func (e *Engine) addVote(vote blockchain.Vote) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tally, ok := e.tallies[vote.BlockHash]
+	if !ok {
+		return
+	}
+	if err := tally.Add(vote); err != nil {
+		log.Printf("Bỏ qua phiếu cho block %s: %v", vote.BlockHash, err)
+	}
+}
+
+// roundResult trả về certificate nếu vòng bỏ phiếu đạt quorum (nil nếu chưa), cùng voting power đã nhận.
+func (e *Engine) roundResult(blockHash string) (*blockchain.CommitCertificate, uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tally, ok := e.tallies[blockHash]
+	if !ok {
+		return nil, 0
+	}
+	if !tally.HasQuorum() {
+		return nil, tally.Power()
+	}
+	return tally.Certificate(), tally.Power()
+}
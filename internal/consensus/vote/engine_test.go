@@ -0,0 +1,61 @@
+package vote
+
+import (
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+	"github.com/chauduongphattien/golang-chain/pkg/storage"
+)
+
+func TestSignVoteRefusesConflictingVotes(t *testing.T) {
+	key, err := network.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := consensus.NewSigner("v1", hex.EncodeToString(network.MarshalPrivateKey(key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "db")
+	db := storage.NewStorage(path)
+	e := New(db, signer, nil)
+
+	if _, err := e.signVote(5, 1, "a"); err != nil {
+		t.Fatalf("phiếu đầu tiên: %v", err)
+	}
+	// Node khởi động lại vẫn nhớ phiếu đã ký
+	db.Close()
+	db = storage.NewStorage(path)
+	defer db.Close()
+	e = New(db, signer, nil)
+
+	tests := []struct {
+		name   string
+		height uint64
+		round  uint64
+		hash   string
+		want   error
+	}{
+		{"ký lại cùng block", 5, 1, "a", nil},
+		{"block khác cùng height và round", 5, 1, "b", ErrConflictingVote},
+		{"round thấp hơn", 5, 0, "b", ErrStaleVote},
+		{"height thấp hơn", 4, 3, "b", ErrStaleVote},
+		{"round cao hơn", 5, 2, "b", nil},
+		{"block cũ ở round đã qua", 5, 1, "a", ErrStaleVote},
+		{"height cao hơn", 6, 0, "c", nil},
+		{"block khác ở height mới", 6, 0, "d", ErrConflictingVote},
+	}
+	for _, tt := range tests {
+		vote, err := e.signVote(tt.height, tt.round, tt.hash)
+		if !errors.Is(err, tt.want) {
+			t.Fatalf("%s: lỗi = %v, mong đợi %v", tt.name, err, tt.want)
+		}
+		if err == nil && (vote.Height != tt.height || vote.Round != tt.round || vote.BlockHash != tt.hash) {
+			t.Errorf("%s: phiếu %+v", tt.name, vote)
+		}
+	}
+}
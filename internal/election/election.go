@@ -0,0 +1,483 @@
+// Package election bầu chọn leader giữa các validator theo kiểu Raft: mỗi nhiệm kỳ (term) có tối đa
+// một leader, leader gửi heartbeat đã ký định kỳ và follower tự ứng cử khi không nghe leader quá lâu.
+package election
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+)
+
+type Role string
+
+const (
+	Follower  Role = "follower"
+	Candidate Role = "candidate"
+	Leader    Role = "leader"
+)
+
+var (
+	ErrStaleTerm   = errors.New("nhiệm kỳ đã cũ")
+	ErrWrongLeader = errors.New("node không phải leader của nhiệm kỳ")
+	// ErrUnknownLeader: node chưa nhận heartbeat hợp lệ nào của leader nhiệm kỳ này
+	ErrUnknownLeader = errors.New("chưa biết leader của nhiệm kỳ")
+)
+
+// electionTick là chu kỳ kiểm tra hết hạn chờ và lịch gửi heartbeat.
+const electionTick = 50 * time.Millisecond
+
+type Config struct {
+	// ElectionTimeout: follower ứng cử nếu không nghe leader sau một khoảng ngẫu nhiên trong [t, 2t)
+	ElectionTimeout   time.Duration
+	HeartbeatInterval time.Duration
+}
+
+// LoadConfig đọc ELECTION_TIMEOUT (mặc định 2s) và HEARTBEAT_INTERVAL (mặc định 500ms).
+func LoadConfig() Config {
+	cfg := Config{
+		ElectionTimeout:   2 * time.Second,
+		HeartbeatInterval: 500 * time.Millisecond,
+	}
+	if raw := os.Getenv("ELECTION_TIMEOUT"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.ElectionTimeout = d
+		} else {
+			log.Printf("ELECTION_TIMEOUT không hợp lệ (%q), dùng %s", raw, cfg.ElectionTimeout)
+		}
+	}
+	if raw := os.Getenv("HEARTBEAT_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.HeartbeatInterval = d
+		} else {
+			log.Printf("HEARTBEAT_INTERVAL không hợp lệ (%q), dùng %s", raw, cfg.HeartbeatInterval)
+		}
+	}
+	return cfg
+}
+
+// Store là phần lưu trữ mà election cần (storage.Storage thoả mãn).
+type Store interface {
+	GetLatestBlock() (*blockchain.Block, error)
+	ValidatorSetAt(height uint64) (*consensus.ValidatorSet, error)
+	LoadElectionState() (term uint64, votedFor string, err error)
+	SaveElectionState(term uint64, votedFor string) error
+}
+
+// Status là trạng thái bầu chọn hiện tại của node.
+type Status struct {
+	NodeID           string `json:"node_id"`
+	Role             Role   `json:"role"`
+	Term             uint64 `json:"term"`
+	LeaderID         string `json:"leader_id,omitempty"`
+	LeaderAddress    string `json:"leader_address,omitempty"`
+	LeaderAPIAddress string `json:"leader_api_address,omitempty"`
+}
+
+type Election struct {
+	mu       sync.Mutex
+	cfg      Config
+	nodeID   string
+	signer   *consensus.Signer
+	store    Store
+	role     Role
+	term     uint64
+	votedFor string
+	leaderID string
+	deadline time.Time
+}
+
+// New khôi phục term và phiếu đã bầu từ store; node luôn bắt đầu ở vai trò follower.
+// signer ký heartbeat khi node làm leader; node không có node key thì không ứng cử.
+func New(signer *consensus.Signer, store Store, cfg Config) (*Election, error) {
+	term, votedFor, err := store.LoadElectionState()
+	if err != nil {
+		return nil, err
+	}
+	e := &Election{
+		cfg:      cfg,
+		nodeID:   signer.ID,
+		signer:   signer,
+		store:    store,
+		role:     Follower,
+		term:     term,
+		votedFor: votedFor,
+	}
+	e.resetDeadline()
+	return e, nil
+}
+
+// Run chạy vòng bầu chọn cho tới khi ctx bị huỷ.
+func (e *Election) Run(ctx context.Context) {
+	ticker := time.NewTicker(electionTick)
+	defer ticker.Stop()
+
+	var nextHeartbeat time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			e.mu.Lock()
+			role, expired := e.role, now.After(e.deadline)
+			e.mu.Unlock()
+
+			switch {
+			case role == Leader:
+				if now.After(nextHeartbeat) {
+					nextHeartbeat = now.Add(e.cfg.HeartbeatInterval)
+					e.broadcastHeartbeat()
+				}
+			case expired:
+				e.campaign()
+				nextHeartbeat = time.Time{}
+			}
+		}
+	}
+}
+
+// validatorSet trả về block cuối và tập validator cho block kế tiếp.
+func (e *Election) validatorSet() (*consensus.ValidatorSet, *blockchain.Block, error) {
+	last, err := e.store.GetLatestBlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	set, err := e.store.ValidatorSetAt(last.Header.Height + 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	return set, last, nil
+}
+
+// campaign tăng nhiệm kỳ, tự bầu cho mình và xin phiếu các validator khác;
+// trở thành leader nếu nhận đa số voting power.
+func (e *Election) campaign() {
+	set, last, err := e.validatorSet()
+	if err != nil {
+		log.Printf("Không tải được tập validator để bầu leader: %v", err)
+		e.mu.Lock()
+		e.resetDeadline()
+		e.mu.Unlock()
+		return
+	}
+	self, ok := set.Get(e.nodeID)
+
+	e.mu.Lock()
+	e.resetDeadline()
+	if !ok || !e.signer.CanSign() {
+		// Node không phải validator hoặc không có node key để ký heartbeat thì không ứng cử
+		e.mu.Unlock()
+		return
+	}
+	e.term++
+	e.role = Candidate
+	e.votedFor = e.nodeID
+	e.leaderID = ""
+	e.persist()
+	term := e.term
+	e.mu.Unlock()
+
+	log.Printf("Ứng cử leader nhiệm kỳ %d (height %d)", term, last.Header.Height)
+
+	vr, err := e.signer.SignVoteRequest(term, last.Header.Height, last.Hash)
+	if err != nil {
+		log.Printf("Không ký được lời xin phiếu: %v", err)
+		return
+	}
+	req := &pb.RequestVoteRequest{
+		Term:        vr.Term,
+		CandidateID: vr.CandidateID,
+		LastHeight:  vr.LastHeight,
+		LastHash:    vr.LastHash,
+		Signature:   vr.Signature,
+	}
+	var (
+		wg      sync.WaitGroup
+		powerMu sync.Mutex
+		power   = self.Power
+	)
+	for _, peer := range set.Peers(e.nodeID) {
+		wg.Add(1)
+		go func(v consensus.Validator) {
+			defer wg.Done()
+			resp, err := grpcclient.RequestVote(v.Address, req, e.cfg.ElectionTimeout/2)
+			if err != nil {
+				return
+			}
+			if resp.Term > term {
+				e.observeTerm(resp.Term)
+				return
+			}
+			if resp.Granted {
+				powerMu.Lock()
+				power += v.Power
+				powerMu.Unlock()
+			}
+		}(peer)
+	}
+	wg.Wait()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.role != Candidate || e.term != term {
+		return
+	}
+	if !set.HasMajority(power) {
+		log.Printf("Không đủ phiếu làm leader nhiệm kỳ %d: %d/%d", term, power, set.TotalPower())
+		return
+	}
+	e.role = Leader
+	e.leaderID = e.nodeID
+	log.Printf("Trở thành leader nhiệm kỳ %d (voting power %d/%d)", term, power, set.TotalPower())
+}
+
+func (e *Election) broadcastHeartbeat() {
+	set, last, err := e.validatorSet()
+	if err != nil {
+		log.Printf("Không tải được tập validator để gửi heartbeat: %v", err)
+		return
+	}
+
+	e.mu.Lock()
+	if e.role != Leader {
+		e.mu.Unlock()
+		return
+	}
+	term := e.term
+	e.mu.Unlock()
+
+	hb, err := e.signer.SignHeartbeat(term, last.Header.Height, last.Hash)
+	if err != nil {
+		log.Printf("Không ký được heartbeat: %v", err)
+		return
+	}
+	req := &pb.HeartbeatRequest{
+		Term:      hb.Term,
+		LeaderID:  hb.LeaderID,
+		Height:    hb.Height,
+		LastHash:  hb.LastHash,
+		Timestamp: hb.Timestamp,
+		Signature: hb.Signature,
+	}
+	var wg sync.WaitGroup
+	for _, peer := range set.Peers(e.nodeID) {
+		wg.Add(1)
+		go func(v consensus.Validator) {
+			defer wg.Done()
+			resp, err := grpcclient.SendHeartbeat(v.Address, req, e.cfg.HeartbeatInterval)
+			if err != nil {
+				return
+			}
+			if resp.Term > term {
+				e.observeTerm(resp.Term)
+			}
+		}(peer)
+	}
+	wg.Wait()
+}
+
+// observeTerm chuyển về follower nếu gặp nhiệm kỳ mới hơn.
+func (e *Election) observeTerm(term uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if term > e.term {
+		e.becomeFollower(term, "")
+	}
+}
+
+// becomeFollower phải được gọi khi đang giữ e.mu.
+func (e *Election) becomeFollower(term uint64, leaderID string) {
+	if term > e.term {
+		e.term = term
+		e.votedFor = ""
+		e.persist()
+	}
+	if e.role == Leader && leaderID != e.nodeID {
+		log.Printf("Thôi làm leader, nhiệm kỳ %d", e.term)
+	}
+	e.role = Follower
+	e.leaderID = leaderID
+	e.resetDeadline()
+}
+
+// resetDeadline phải được gọi khi đang giữ e.mu.
+func (e *Election) resetDeadline() {
+	jitter := time.Duration(rand.Int63n(int64(e.cfg.ElectionTimeout)))
+	e.deadline = time.Now().Add(e.cfg.ElectionTimeout + jitter)
+}
+
+// persist phải được gọi khi đang giữ e.mu.
+func (e *Election) persist() {
+	if err := e.store.SaveElectionState(e.term, e.votedFor); err != nil {
+		log.Printf("Lưu trạng thái bầu chọn thất bại: %v", err)
+	}
+}
+
+// HandleRequestVote bỏ phiếu cho ứng viên nếu nhiệm kỳ không cũ, chưa bầu cho ai khác trong
+// nhiệm kỳ này và chuỗi của ứng viên không kém chuỗi của node: dài hơn, hoặc cùng height và cùng block cuối.
+// Lời xin phiếu phải được ký bởi một validator trong tập; lời xin phiếu không hợp lệ bị bỏ qua trước khi
+// node ghi nhận nhiệm kỳ của nó, để không ai đẩy nhiệm kỳ lên và hạ leader thay cho validator.
+func (e *Election) HandleRequestVote(req *pb.RequestVoteRequest) *pb.RequestVoteResponse {
+	last, err := e.verifyVoteRequest(req)
+	if err != nil {
+		log.Printf("Bỏ qua lời xin phiếu của %s nhiệm kỳ %d: %v", req.CandidateID, req.Term, err)
+		return &pb.RequestVoteResponse{Term: e.Term(), Granted: false}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if req.Term < e.term {
+		return &pb.RequestVoteResponse{Term: e.term, Granted: false}
+	}
+	if req.Term > e.term {
+		e.becomeFollower(req.Term, "")
+	}
+
+	if e.votedFor != "" && e.votedFor != req.CandidateID {
+		return &pb.RequestVoteResponse{Term: e.term, Granted: false}
+	}
+	if req.LastHeight < last.Header.Height {
+		return &pb.RequestVoteResponse{Term: e.term, Granted: false}
+	}
+	if req.LastHeight == last.Header.Height && req.LastHash != last.Hash {
+		// Cùng height nhưng khác nhánh
+		return &pb.RequestVoteResponse{Term: e.term, Granted: false}
+	}
+
+	e.votedFor = req.CandidateID
+	e.persist()
+	e.resetDeadline()
+	log.Printf("Bầu %s làm leader nhiệm kỳ %d", req.CandidateID, req.Term)
+	return &pb.RequestVoteResponse{Term: e.term, Granted: true}
+}
+
+// verifyVoteRequest kiểm tra chữ ký của lời xin phiếu và trả về block cuối của node.
+func (e *Election) verifyVoteRequest(req *pb.RequestVoteRequest) (*blockchain.Block, error) {
+	set, last, err := e.validatorSet()
+	if err != nil {
+		return nil, err
+	}
+	vr := blockchain.VoteRequest{
+		Term:        req.Term,
+		CandidateID: req.CandidateID,
+		LastHeight:  req.LastHeight,
+		LastHash:    req.LastHash,
+		Signature:   req.Signature,
+	}
+	if _, err := set.VerifyVoteRequest(&vr); err != nil {
+		return nil, err
+	}
+	return last, nil
+}
+
+// HandleHeartbeat ghi nhận leader của nhiệm kỳ hiện tại và đặt lại thời gian chờ. Heartbeat phải được
+// ký bởi một validator trong tập và có timestamp cách giờ local không quá ElectionTimeout, để heartbeat
+// giả mạo hay heartbeat cũ bị gửi lại không chiếm được vai trò leader hoặc ngăn bầu leader mới.
+func (e *Election) HandleHeartbeat(req *pb.HeartbeatRequest) *pb.HeartbeatResponse {
+	if err := e.verifyHeartbeat(req); err != nil {
+		log.Printf("Bỏ qua heartbeat của %s nhiệm kỳ %d: %v", req.LeaderID, req.Term, err)
+		return &pb.HeartbeatResponse{Term: e.Term(), Success: false}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if req.Term < e.term {
+		return &pb.HeartbeatResponse{Term: e.term, Success: false}
+	}
+	if req.Term > e.term || e.leaderID != req.LeaderID {
+		log.Printf("Leader nhiệm kỳ %d: %s", req.Term, req.LeaderID)
+	}
+	e.becomeFollower(req.Term, req.LeaderID)
+	return &pb.HeartbeatResponse{Term: e.term, Success: true}
+}
+
+func (e *Election) verifyHeartbeat(req *pb.HeartbeatRequest) error {
+	hb := blockchain.Heartbeat{
+		Term:      req.Term,
+		LeaderID:  req.LeaderID,
+		Height:    req.Height,
+		LastHash:  req.LastHash,
+		Timestamp: req.Timestamp,
+		Signature: req.Signature,
+	}
+	age := time.Since(time.UnixMilli(hb.Timestamp))
+	if age > e.cfg.ElectionTimeout || age < -e.cfg.ElectionTimeout {
+		return fmt.Errorf("timestamp lệch %s so với giờ local", age.Round(time.Millisecond))
+	}
+	set, _, err := e.validatorSet()
+	if err != nil {
+		return err
+	}
+	_, err = set.VerifyHeartbeat(&hb)
+	return err
+}
+
+// AcceptLeader kiểm tra proposal đến từ leader của nhiệm kỳ term mà node đã biết qua heartbeat đã ký;
+// proposal không tự làm đổi leader hay nhiệm kỳ.
+func (e *Election) AcceptLeader(term uint64, leaderID string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if term < e.term {
+		return fmt.Errorf("%w: %d < %d", ErrStaleTerm, term, e.term)
+	}
+	if term > e.term || e.leaderID == "" {
+		return fmt.Errorf("%w %d", ErrUnknownLeader, term)
+	}
+	if e.leaderID != leaderID {
+		return fmt.Errorf("%w %d: %s (leader là %s)", ErrWrongLeader, term, leaderID, e.leaderID)
+	}
+	e.becomeFollower(term, leaderID)
+	return nil
+}
+
+func (e *Election) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.role == Leader
+}
+
+func (e *Election) Term() uint64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.term
+}
+
+// Leader trả về validator đang là leader, false nếu chưa biết leader.
+func (e *Election) Leader() (consensus.Validator, bool) {
+	e.mu.Lock()
+	leaderID := e.leaderID
+	e.mu.Unlock()
+	if leaderID == "" {
+		return consensus.Validator{}, false
+	}
+
+	set, _, err := e.validatorSet()
+	if err != nil {
+		return consensus.Validator{}, false
+	}
+	return set.Get(leaderID)
+}
+
+func (e *Election) Status() Status {
+	e.mu.Lock()
+	status := Status{NodeID: e.nodeID, Role: e.role, Term: e.term, LeaderID: e.leaderID}
+	e.mu.Unlock()
+
+	if leader, ok := e.Leader(); ok {
+		status.LeaderAddress = leader.Address
+		status.LeaderAPIAddress = leader.APIAddress
+	}
+	return status
+}
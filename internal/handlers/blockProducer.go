@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+)
+
+// ProducerConfig cấu hình vòng lặp tự tạo block của leader.
+type ProducerConfig struct {
+	Enabled          bool
+	Interval         time.Duration // tạo block sau mỗi khoảng này
+	MempoolThreshold int           // tạo block sớm khi mempool đạt số giao dịch này (0 = tắt)
+	SkipEmpty        bool          // không tạo block rỗng
+}
+
+// LoadProducerConfig đọc cấu hình từ biến môi trường BLOCK_PRODUCER, BLOCK_INTERVAL,
+// BLOCK_MEMPOOL_THRESHOLD và SKIP_EMPTY_BLOCKS.
+func LoadProducerConfig() ProducerConfig {
+	cfg := ProducerConfig{
+		Enabled:          os.Getenv("BLOCK_PRODUCER") == "on",
+		Interval:         5 * time.Second,
+		MempoolThreshold: 100,
+		SkipEmpty:        true,
+	}
+
+	if raw := os.Getenv("BLOCK_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.Interval = d
+		} else {
+			log.Printf("BLOCK_INTERVAL không hợp lệ (%q), dùng %s", raw, cfg.Interval)
+		}
+	}
+	if raw := os.Getenv("BLOCK_MEMPOOL_THRESHOLD"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			cfg.MempoolThreshold = n
+		} else {
+			log.Printf("BLOCK_MEMPOOL_THRESHOLD không hợp lệ (%q), dùng %d", raw, cfg.MempoolThreshold)
+		}
+	}
+	if raw := os.Getenv("SKIP_EMPTY_BLOCKS"); raw != "" {
+		if b, err := strconv.ParseBool(raw); err == nil {
+			cfg.SkipEmpty = b
+		}
+	}
+	return cfg
+}
+
+// mempoolPollInterval là chu kỳ kiểm tra ngưỡng mempool giữa hai lần tạo block định kỳ.
+const mempoolPollInterval = 250 * time.Millisecond
+
+// RunBlockProducer tạo, đề xuất và commit block theo chu kỳ hoặc khi mempool đủ lớn,
+// cho tới khi ctx bị huỷ. Chỉ tạo block khi engine đồng thuận cho phép (với engine bỏ phiếu: khi node là leader). Các endpoint /leader/genBlock và /leader/proposal vẫn dùng được để debug.
+func (h *LeaderHandler) RunBlockProducer(ctx context.Context, cfg ProducerConfig) {
+	log.Printf("Bắt đầu tự tạo block: mỗi %s, ngưỡng mempool %d, bỏ block rỗng: %v",
+		cfg.Interval, cfg.MempoolThreshold, cfg.SkipEmpty)
+
+	interval := time.NewTicker(cfg.Interval)
+	defer interval.Stop()
+	poll := time.NewTicker(mempoolPollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-interval.C:
+			h.produceBlock(!cfg.SkipEmpty)
+		case <-poll.C:
+			if cfg.MempoolThreshold > 0 && h.memPool.Size() >= cfg.MempoolThreshold {
+				h.produceBlock(false)
+				interval.Reset(cfg.Interval)
+			}
+		}
+	}
+}
+
+func (h *LeaderHandler) produceBlock(allowEmpty bool) {
+	block, err := h.buildBlock(allowEmpty)
+	if err != nil {
+		if !errors.Is(err, errEmptyMempool) && !errors.Is(err, ErrPendingInProgress) && !errors.Is(err, consensus.ErrNotProposer) {
+			log.Printf("Tự tạo block thất bại: %v", err)
+		}
+		return
+	}
+
+	if err := h.proposePending(); err != nil {
+		log.Printf("Block %d (%s) không được commit: %v", block.Header.Height, block.Hash, err)
+		return
+	}
+	log.Printf("Đã commit block %d (%s) với %d giao dịch", block.Header.Height, block.Hash, len(block.Transactions))
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
+	"strconv"
 
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
 	"github.com/chauduongphattien/golang-chain/internal/network"
 	"github.com/chauduongphattien/golang-chain/pkg/storage"
 )
@@ -24,13 +27,17 @@ func (h *CommonHandler) HelloHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 type WalletResponse struct {
-	Address string `json:"address"`
-	Token   int    `json:"token"`
+	Address   string            `json:"address"`
+	PublicKey string            `json:"public_key"`
+	Token     blockchain.Amount `json:"token"`
+	Nonce     uint64            `json:"nonce"`
 }
 
+// CreateWalletRequest chỉ nhận public key (hex, X||Y); node không bao giờ thấy private key.
+// Số dư không được tạo ở đây mà chỉ đến từ genesis hoặc giao dịch trên chuỗi.
 type CreateWalletRequest struct {
-	Name  string `json:"name"`
-	Token int    `json:"token"`
+	Name      string `json:"name"`
+	PublicKey string `json:"public_key"`
 }
 
 func (h *CommonHandler) CreateWalletHandler(w http.ResponseWriter, r *http.Request) {
@@ -54,14 +61,26 @@ func (h *CommonHandler) CreateWalletHandler(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	newWallet := network.NewWallet(req.Token)
-	if err := h.storageInst.SaveWallet(newWallet.Address, newWallet); err != nil {
+	pubKey, err := hex.DecodeString(req.PublicKey)
+	if err != nil {
+		http.Error(w, "public_key phải là chuỗi hex", http.StatusBadRequest)
+		return
+	}
+	if _, err := network.ParsePublicKey(pubKey); err != nil {
+		http.Error(w, "Public key không hợp lệ", http.StatusBadRequest)
+		return
+	}
+
+	// Địa chỉ đã có số dư (genesis hoặc được chuyển tiền) -> chỉ gắn public key
+	newWallet, err := h.storageInst.BindPublicKey(pubKey)
+	if err != nil {
 		http.Error(w, "Không thể lưu ví", http.StatusInternalServerError)
 		return
 	}
 	resp := WalletResponse{
-		Address: newWallet.Address,
-		Token:   newWallet.Token,
+		Address:   newWallet.Address,
+		PublicKey: hex.EncodeToString(newWallet.PublicKey),
+		Token:     newWallet.Token,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -90,8 +109,10 @@ func (h *CommonHandler) GetWalletHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	resp := WalletResponse{
-		Address: walletData.Address,
-		Token:   walletData.Token,
+		Address:   walletData.Address,
+		PublicKey: hex.EncodeToString(walletData.PublicKey),
+		Token:     walletData.Token,
+		Nonce:     walletData.Nonce,
 	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
@@ -113,6 +134,61 @@ func (h *CommonHandler) GetAllWalletsHandler(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(wallets)
 }
 
+func (h *CommonHandler) GetBlockByHeight(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	height, err := strconv.ParseUint(r.URL.Query().Get("height"), 10, 64)
+	if err != nil {
+		http.Error(w, "height không hợp lệ", http.StatusBadRequest)
+		return
+	}
+
+	block, err := h.storageInst.LoadBlockByHeight(height)
+	if err != nil {
+		http.Error(w, "Không tìm thấy block", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(block)
+}
+
+// GetValidatorSet trả về tập validator có hiệu lực tại height; mặc định là tập dùng cho block kế tiếp.
+func (h *CommonHandler) GetValidatorSet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var height uint64
+	if raw := r.URL.Query().Get("height"); raw != "" {
+		var err error
+		if height, err = strconv.ParseUint(raw, 10, 64); err != nil {
+			http.Error(w, "height không hợp lệ", http.StatusBadRequest)
+			return
+		}
+	} else {
+		last, err := h.storageInst.GetLatestBlock()
+		if err != nil {
+			http.Error(w, "Không tải được block cuối", http.StatusInternalServerError)
+			return
+		}
+		height = last.Header.Height + 1
+	}
+
+	set, err := h.storageInst.ValidatorSetAt(height)
+	if err != nil {
+		http.Error(w, "Không tìm thấy tập validator", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(set)
+}
+
 func (h *CommonHandler) GetLastBlock(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
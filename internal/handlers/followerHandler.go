@@ -2,64 +2,78 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"os"
 
-	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/peers"
+	"github.com/chauduongphattien/golang-chain/internal/syncer"
 	"github.com/chauduongphattien/golang-chain/pkg/storage"
 )
 
 type FollowerHandler struct {
 	storageInst *storage.Storage
-	leaderAddr  string
+	engine      consensus.Engine
+	syncer      *syncer.Syncer
+	peers       *peers.Manager
 }
 
-func NewFollowerHandler(storage *storage.Storage) *FollowerHandler {
+func NewFollowerHandler(storage *storage.Storage, engine consensus.Engine, sync *syncer.Syncer, manager *peers.Manager) *FollowerHandler {
 	return &FollowerHandler{
 		storageInst: storage,
-		leaderAddr:  getLeaderAddr(),
+		engine:      engine,
+		syncer:      sync,
+		peers:       manager,
 	}
 }
 
-func getLeaderAddr() string {
-	raw := os.Getenv("LEADER")
-	if raw == "" {
-		raw = "leader:50050"
+// syncSource trả về địa chỉ gRPC của leader đã bầu; nếu chưa biết leader thì dùng peer còn sống
+// có chuỗi cao nhất.
+func (h *FollowerHandler) syncSource() (string, bool) {
+	if leader, ok := h.engine.Leader(); ok {
+		return leader.Address, true
 	}
-	return raw
+	if peer, ok := h.peers.Best(); ok {
+		return peer.Address, true
+	}
+	return "", false
 }
 
+// HandleSyncBlock tải từ leader (hoặc peer cao nhất) các block còn thiếu (xem syncer.Syncer)
+// và trả về tiến độ đồng bộ.
 func (h *FollowerHandler) HandleSyncBlock(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Chỉ hỗ trợ POST", http.StatusMethodNotAllowed)
 		return
 	}
 
-	lastBlock, err := h.storageInst.GetLatestBlock()
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Lỗi đồng bộ từ leader, get Block cuoi that bai: %v", err), http.StatusInternalServerError)
+	address, ok := h.syncSource()
+	if !ok {
+		http.Error(w, "Chưa biết leader hay peer nào để đồng bộ", http.StatusServiceUnavailable)
 		return
 	}
-
-	blocks, err := grpcclient.SyncFromLeader(h.leaderAddr, lastBlock.Hash)
+	progress, err := h.syncer.Sync(r.Context(), address)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Lỗi đồng bộ từ leader: %v", err), http.StatusInternalServerError)
-		return
-	}
-
-	for _, block := range blocks {
-		err := h.storageInst.SaveBlock(block)
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Lỗi lưu block về local: %v", err), http.StatusInternalServerError)
-			return
+		status := http.StatusBadGateway
+		if errors.Is(err, syncer.ErrNoCommonAncestor) {
+			status = http.StatusConflict
 		}
+		http.Error(w, fmt.Sprintf("Lỗi đồng bộ từ %s: %v", address, err), status)
+		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	err = json.NewEncoder(w).Encode(blocks)
-	if err != nil {
-		http.Error(w, "Lỗi encode kết quả", http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(progress)
+}
+
+// GetSyncStatusHandler trả về trạng thái đồng bộ: height hiện tại, height cao nhất của các peer và tiến độ.
+func (h *FollowerHandler) GetSyncStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
 		return
 	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.syncer.Status())
 }
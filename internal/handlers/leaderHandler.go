@@ -1,21 +1,23 @@
 package handlers
 
 import (
+	"context"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
-	"os"
-	"strings"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/mempool"
 	"github.com/chauduongphattien/golang-chain/internal/network"
-	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/gossip"
 	"github.com/chauduongphattien/golang-chain/pkg/storage"
-
-	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
-	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
 )
 
 type VoteRequest struct {
@@ -23,31 +25,69 @@ type VoteRequest struct {
 	Accepted  bool   `json:"accepted"`
 }
 
+// Giới hạn kích thước của một block khi lấy giao dịch từ mempool
+const (
+	maxBlockTxs   = 500
+	maxBlockBytes = 1 << 20
+)
+
 type LeaderHandler struct {
-	memPool       []blockchain.Transaction
-	storageInst   *storage.Storage
-	voteCount     int
-	voteMu        sync.Mutex
-	totalVotes    int
-	pendingBlk    *blockchain.Block
-	followerAddrs []string
+	memPool     *mempool.Mempool
+	storageInst *storage.Storage
+	engine      consensus.Engine
+	gossip      *gossip.Gossip
+	pendingMu   sync.Mutex
+	pending     *PendingBlock
+	nodeID      string
 }
 
-func NewLeaderHandler(storage *storage.Storage) *LeaderHandler {
-	return &LeaderHandler{
-		memPool:       []blockchain.Transaction{},
-		storageInst:   storage,
-		voteCount:     0,
-		followerAddrs: getFollowerAddrs(),
+// NewLeaderHandler dùng chung pool với txGossip: giao dịch nhận qua HTTP hay từ peer đều vào pool này.
+func NewLeaderHandler(storage *storage.Storage, engine consensus.Engine, pool *mempool.Mempool, txGossip *gossip.Gossip, nodeID string) *LeaderHandler {
+	h := &LeaderHandler{
+		memPool:     pool,
+		storageInst: storage,
+		engine:      engine,
+		gossip:      txGossip,
+		nodeID:      nodeID,
 	}
+	storage.OnChainUpdate(h.onChainUpdate)
+	return h
 }
 
-func getFollowerAddrs() []string {
-	raw := os.Getenv("FOLLOWERS")
-	if raw == "" {
-		raw = "follower1:50051,follower1:50052"
+// onChainUpdate đồng bộ mempool với chuỗi chính: giao dịch của các block bị bỏ khi đổi nhánh
+// được trả lại mempool (nếu chưa có trong nhánh mới), giao dịch đã vào block bị xoá.
+func (h *LeaderHandler) onChainUpdate(update *storage.ChainUpdate) {
+	var applied []blockchain.Transaction
+	included := make(map[string]bool)
+	for _, block := range update.Applied {
+		for _, tx := range block.Transactions {
+			included[tx.ID()] = true
+			applied = append(applied, tx)
+		}
+	}
+
+	var orphaned []blockchain.Transaction
+	for _, block := range update.Orphaned {
+		for _, tx := range block.Transactions {
+			if !included[tx.ID()] {
+				orphaned = append(orphaned, tx)
+			}
+		}
+	}
+	sort.SliceStable(orphaned, func(i, j int) bool { return orphaned[i].Nonce < orphaned[j].Nonce })
+
+	reinserted := 0
+	for _, tx := range orphaned {
+		if err := h.memPool.Add(tx); err != nil {
+			log.Printf("Bỏ giao dịch %s của block bị bỏ khỏi chuỗi chính: %v", tx.ID(), err)
+			continue
+		}
+		reinserted++
 	}
-	return strings.Split(raw, ",")
+	if len(orphaned) > 0 {
+		log.Printf("Trả lại mempool %d/%d giao dịch của các block bị bỏ", reinserted, len(orphaned))
+	}
+	h.memPool.Remove(applied)
 }
 
 func (h *LeaderHandler) Hello(w http.ResponseWriter, r *http.Request) {
@@ -60,10 +100,17 @@ func (h *LeaderHandler) Hello(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("Hello from LeaderHandler!"))
 }
 
+// TransRequest là giao dịch đã được client ký sẵn bằng private key của mình.
+// PublicKey và Signature được mã hoá hex.
 type TransRequest struct {
-	Sender   string `json:"sender"`
-	Receiver string `json:"receiver"`
-	Amount   int    `json:"amount"`
+	Sender    string            `json:"sender"`
+	Receiver  string            `json:"receiver"`
+	Amount    blockchain.Amount `json:"amount"`
+	Fee       blockchain.Amount `json:"fee"`
+	Timestamp int64             `json:"timestamp"`
+	Nonce     uint64            `json:"nonce"`
+	PublicKey string            `json:"public_key"`
+	Signature string            `json:"signature"`
 }
 
 func (h *LeaderHandler) GetMemPoolHandler(w http.ResponseWriter, r *http.Request) {
@@ -73,9 +120,11 @@ func (h *LeaderHandler) GetMemPoolHandler(w http.ResponseWriter, r *http.Request
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(h.memPool)
+	json.NewEncoder(w).Encode(h.memPool.Snapshot())
 }
 
+// HandleTransaction nhận giao dịch trên mọi node; giao dịch hợp lệ được thêm vào mempool
+// và chuyển tiếp cho các peer nên leader nào cũng có thể đưa vào block.
 func (h *LeaderHandler) HandleTransaction(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Chỉ hỗ trợ POST", http.StatusMethodNotAllowed)
@@ -88,50 +137,51 @@ func (h *LeaderHandler) HandleTransaction(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	walletData, err := h.storageInst.LoadWallet(trans.Sender)
+	pubKeyBytes, err := hex.DecodeString(trans.PublicKey)
 	if err != nil {
-		http.Error(w, "Không tìm thấy ví", http.StatusNotFound)
+		http.Error(w, "public_key phải là chuỗi hex", http.StatusBadRequest)
 		return
 	}
-
-	if walletData.Token < trans.Amount {
-		http.Error(w, "Số dư không đủ", http.StatusBadRequest)
+	signature, err := hex.DecodeString(trans.Signature)
+	if err != nil || len(signature) == 0 {
+		http.Error(w, "Thiếu chữ ký hoặc chữ ký không phải hex", http.StatusBadRequest)
+		return
+	}
+	if !blockchain.ValidAddress(trans.Receiver) {
+		http.Error(w, "receiver phải là địa chỉ ví (64 ký tự hex)", http.StatusBadRequest)
+		return
+	}
+	if network.GetAddressFromPubKey(pubKeyBytes) != trans.Sender {
+		http.Error(w, "Public key không khớp với địa chỉ gửi", http.StatusBadRequest)
 		return
 	}
 
-	pubKey, err := network.ParsePublicKey(walletData.PublicKey)
+	pubKey, err := network.ParsePublicKey(pubKeyBytes)
 	if err != nil {
 		http.Error(w, "Public key không hợp lệ", http.StatusBadRequest)
 		return
 	}
 
-	timestamp := time.Now().Unix()
 	tx := &blockchain.Transaction{
 		Sender:    trans.Sender,
 		Receiver:  trans.Receiver,
-		Amount:    float64(trans.Amount),
-		Timestamp: timestamp,
-	}
-
-	privateKey, err := network.ParsePrivateKey(walletData.PrivateKey)
-	if err != nil {
-		http.Error(w, "Private key không hợp lệ", http.StatusBadRequest)
-		return
-	}
-
-	sign, err := network.GenerateSignature(tx, privateKey)
-	if err != nil {
-		http.Error(w, "Không thể tạo chữ ký", http.StatusInternalServerError)
-		return
+		Amount:    trans.Amount,
+		Fee:       trans.Fee,
+		Timestamp: trans.Timestamp,
+		Signature: signature,
+		PublicKey: pubKeyBytes,
+		Nonce:     trans.Nonce,
 	}
-	tx.Signature = sign
 
 	if !network.VerifyTransaction(tx, pubKey) {
 		http.Error(w, "Giao dịch không hợp lệ (sai chữ ký)", http.StatusBadRequest)
 		return
 	}
 
-	h.memPool = append(h.memPool, *tx)
+	if err := h.gossip.Submit(*tx, ""); err != nil {
+		http.Error(w, "Không nhận giao dịch: "+err.Error(), mempoolErrorStatus(err))
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{
@@ -139,6 +189,17 @@ func (h *LeaderHandler) HandleTransaction(w http.ResponseWriter, r *http.Request
 	})
 }
 
+func mempoolErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, mempool.ErrDuplicate), errors.Is(err, mempool.ErrNonceExists), errors.Is(err, gossip.ErrSeen):
+		return http.StatusConflict
+	case errors.Is(err, mempool.ErrPoolFull), errors.Is(err, mempool.ErrAccountFull):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 type ProposalRequest struct {
 	Block    *blockchain.Block `json:"block"`
 	LeaderID string            `json:"leader_id"`
@@ -149,26 +210,111 @@ func (h *LeaderHandler) CreateBlockHandler(w http.ResponseWriter, r *http.Reques
 		http.Error(w, "Chỉ hỗ trợ POST", http.StatusMethodNotAllowed)
 		return
 	}
+	if h.redirectToLeader(w, r) {
+		return
+	}
+
+	newBlock, err := h.buildBlock(false)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrPendingInProgress) || errors.Is(err, errEmptyMempool) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newBlock)
+}
+
+var errEmptyMempool = errors.New("không có giao dịch trong memPool")
+
+// redirectToLeader chuyển client tới leader hiện tại (307, giữ nguyên method và body)
+// nếu node này không được tạo block. Trả về true nếu request đã được xử lý.
+func (h *LeaderHandler) redirectToLeader(w http.ResponseWriter, r *http.Request) bool {
+	if h.engine.CanPropose() {
+		return false
+	}
+	leader, ok := h.engine.Leader()
+	if !ok || leader.APIAddress == "" {
+		http.Error(w, "Chưa có leader, thử lại sau", http.StatusServiceUnavailable)
+		return true
+	}
+	w.Header().Set("X-Leader-ID", leader.ID)
+	http.Redirect(w, r, "http://"+leader.APIAddress+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	return true
+}
 
-	if len(h.memPool) == 0 {
-		http.Error(w, "Không có giao dịch trong memPool", http.StatusBadRequest)
+// GetLeaderStatusHandler trả về trạng thái của engine đồng thuận (với engine bỏ phiếu:
+// vai trò của node, nhiệm kỳ và leader hiện tại).
+func (h *LeaderHandler) GetLeaderStatusHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
 		return
 	}
 
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.engine.Status())
+}
+
+// buildBlock lấy giao dịch từ mempool, tạo block nối tiếp block cuối và đặt làm block đang chờ.
+// Các giao dịch được chọn bị gỡ khỏi mempool cho tới khi block được commit hoặc bị huỷ.
+func (h *LeaderHandler) buildBlock(allowEmpty bool) (*blockchain.Block, error) {
+	if !h.engine.CanPropose() {
+		return nil, consensus.ErrNotProposer
+	}
+
+	h.pendingMu.Lock()
+	busy := h.pending.active()
+	h.pendingMu.Unlock()
+	if busy {
+		return nil, ErrPendingInProgress
+	}
+
+	txs := h.applicableTxs(h.memPool.Reap(maxBlockTxs, maxBlockBytes))
+	if len(txs) == 0 && !allowEmpty {
+		return nil, errEmptyMempool
+	}
+
 	lastBlock, err := h.storageInst.GetLatestBlock()
-	var prevHash string
-	if err == nil && lastBlock != nil {
-		prevHash = lastBlock.Hash
+	if err != nil {
+		return nil, errors.New("không tải được block cuối")
+	}
+
+	stateRoot, err := h.storageInst.NextStateRoot(lastBlock, txs)
+	if err != nil {
+		return nil, fmt.Errorf("không tính được state root: %w", err)
 	}
 
 	timestamp := time.Now().Unix()
-	newBlock := blockchain.NewBlock(h.memPool, prevHash, timestamp)
+	newBlock := blockchain.NewBlock(lastBlock, txs, stateRoot, timestamp, h.nodeID)
+	if err := h.engine.Propose(context.Background(), newBlock); err != nil {
+		return nil, fmt.Errorf("không hoàn thiện được block: %w", err)
+	}
 
-	h.pendingBlk = newBlock
-	h.memPool = []blockchain.Transaction{} // Clear mempool
+	if err := h.setPending(newBlock); err != nil {
+		return nil, err
+	}
+	h.memPool.Remove(txs)
+	return newBlock, nil
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(newBlock)
+// applicableTxs giữ lại các giao dịch áp dụng được tuần tự lên trạng thái hiện tại,
+// để một giao dịch lỗi không làm cả block bị từ chối.
+func (h *LeaderHandler) applicableTxs(txs []blockchain.Transaction) []blockchain.Transaction {
+	applied := blockchain.StateChanges{}
+	var selected []blockchain.Transaction
+	for _, tx := range txs {
+		changes, err := blockchain.ApplyTransactions(applied.Overlay(h.storageInst), []blockchain.Transaction{tx})
+		if err != nil {
+			log.Printf("Bỏ qua giao dịch %s khi tạo block: %v", tx.ID(), err)
+			continue
+		}
+		applied.Merge(changes)
+		selected = append(selected, tx)
+	}
+	return selected
 }
 
 func (h *LeaderHandler) SendProposal(w http.ResponseWriter, r *http.Request) {
@@ -176,74 +322,36 @@ func (h *LeaderHandler) SendProposal(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Chỉ hỗ trợ POST", http.StatusMethodNotAllowed)
 		return
 	}
-
-	if h.pendingBlk == nil {
+	if h.redirectToLeader(w, r) {
 		return
 	}
 
-	h.GenProposeBlock(h.pendingBlk, h.followerAddrs)
+	if err := h.proposePending(); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte("Đã gửi proposal đến các follower"))
+	w.Write([]byte("Block đã được commit"))
 }
 
-func (h *LeaderHandler) GenProposeBlock(b *blockchain.Block, followerAddrs []string) {
-	protoBlock := utils.ConvertToProtoBlock(b)
-
-	req := &pb.ProposalRequest{
-		Block:    protoBlock,
-		LeaderID: "leader-1",
+// proposePending đề xuất block đang chờ; nếu không đạt đồng thuận thì huỷ block
+// và trả giao dịch về mempool.
+func (h *LeaderHandler) proposePending() error {
+	block, err := h.markProposed()
+	if err != nil {
+		return err
 	}
-	var wg sync.WaitGroup
-	for _, addr := range followerAddrs {
-		wg.Add(1)
-
-		go func(address string) {
-			defer wg.Done()
-			resp, err := grpcclient.SendProposalToFollower(address, req)
-			if err != nil {
-				log.Printf("Gửi proposal đến %s thất bại: %v\n", address, err)
-				return
-			}
-			log.Printf("Follower %s phản hồi: %s (accepted: %v)\n", address, resp.Message, resp.Accepted)
-			if resp.Accepted {
-				h.voteMu.Lock()
-				h.voteCount++
-				h.voteMu.Unlock()
-			}
-		}(addr)
+	if !h.engine.CanPropose() {
+		h.abortPending(consensus.ErrNotProposer.Error())
+		return consensus.ErrNotProposer
 	}
 
-	wg.Wait()
-
-	log.Printf("Số phiếu đồng thuận nhận được: %d\n", h.voteCount)
-
-	if h.voteCount >= 2 {
-		log.Println("Đủ phiếu, tiến hành gửi commit đến follower")
-
-		commitReq := &pb.CommitBlockRequest{
-			Block: protoBlock,
-		}
-		errSaveBlk := h.storageInst.SaveBlock(b)
-		if errSaveBlk != nil {
-			log.Printf("save block ở leader thất bại")
-			return
-		}
-		for _, addr := range followerAddrs {
-			wg.Add(1)
-			go func(address string) {
-				defer wg.Done()
-				resp, err := grpcclient.SendCommitBlockToFollower(address, commitReq)
-				if err != nil {
-					log.Printf("Gửi commit đến %s thất bại: %v\n", address, err)
-					return
-				}
-				log.Printf("Commit xác nhận từ %s: %s (success: %v)\n", address, resp.Message, resp.Success)
-			}(addr)
-		}
-		wg.Wait()
-	} else {
-		log.Println("Không đủ phiếu, không gửi commit")
+	if err := h.engine.Finalize(context.Background(), block); err != nil {
+		h.abortPending(err.Error())
+		h.engine.OnTimeout(block.Header.Height)
+		return err
 	}
-
+	h.markCommitted()
+	return nil
 }
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/p2p/peers"
+)
+
+type PeerHandler struct {
+	peers *peers.Manager
+}
+
+func NewPeerHandler(manager *peers.Manager) *PeerHandler {
+	return &PeerHandler{
+		peers: manager,
+	}
+}
+
+// GetPeersHandler trả về mọi peer mà node đang biết: trạng thái sống, height, điểm và thời hạn bị cấm.
+func (h *PeerHandler) GetPeersHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	type peerView struct {
+		peers.Peer
+		Alive  bool `json:"alive"`
+		Banned bool `json:"banned"`
+	}
+	now := time.Now()
+	list := h.peers.Peers()
+	views := make([]peerView, 0, len(list))
+	for _, p := range list {
+		views = append(views, peerView{Peer: p, Alive: p.Alive(), Banned: p.Banned(now)})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(views)
+}
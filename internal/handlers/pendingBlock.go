@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+// PendingStatus là trạng thái của block leader đang xử lý:
+// built -> proposed -> committed | aborted.
+type PendingStatus string
+
+const (
+	PendingBuilt     PendingStatus = "built"
+	PendingProposed  PendingStatus = "proposed"
+	PendingCommitted PendingStatus = "committed"
+	PendingAborted   PendingStatus = "aborted"
+)
+
+var (
+	ErrNoPendingBlock     = errors.New("không có block đang chờ")
+	ErrPendingInProgress  = errors.New("đang có block chờ đề xuất hoặc commit")
+	ErrPendingNotProposal = errors.New("block đang chờ không ở trạng thái built")
+)
+
+type PendingBlock struct {
+	Block      *blockchain.Block `json:"block"`
+	Status     PendingStatus     `json:"status"`
+	Reason     string            `json:"reason,omitempty"`
+	Reinserted int               `json:"reinserted,omitempty"`
+	Dropped    int               `json:"dropped,omitempty"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+func (p *PendingBlock) active() bool {
+	return p != nil && (p.Status == PendingBuilt || p.Status == PendingProposed)
+}
+
+// setPending đặt block mới làm block đang chờ nếu không còn block nào đang xử lý.
+func (h *LeaderHandler) setPending(block *blockchain.Block) error {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	if h.pending.active() {
+		return ErrPendingInProgress
+	}
+	h.pending = &PendingBlock{Block: block, Status: PendingBuilt, UpdatedAt: time.Now()}
+	return nil
+}
+
+// markProposed chuyển block đang chờ sang proposed và trả về block đó.
+func (h *LeaderHandler) markProposed() (*blockchain.Block, error) {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	if h.pending == nil {
+		return nil, ErrNoPendingBlock
+	}
+	if h.pending.Status != PendingBuilt {
+		return nil, ErrPendingNotProposal
+	}
+	h.pending.Status = PendingProposed
+	h.pending.UpdatedAt = time.Now()
+	return h.pending.Block, nil
+}
+
+func (h *LeaderHandler) markCommitted() {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	h.pending.Status = PendingCommitted
+	h.pending.UpdatedAt = time.Now()
+}
+
+// abortPending huỷ block đang chờ và trả các giao dịch về mempool. Mempool kiểm tra lại
+// từng giao dịch với trạng thái mới nhất; giao dịch không còn hợp lệ bị loại.
+func (h *LeaderHandler) abortPending(reason string) {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	if !h.pending.active() {
+		return
+	}
+
+	txs := append([]blockchain.Transaction(nil), h.pending.Block.Transactions...)
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].Nonce < txs[j].Nonce })
+
+	reinserted, dropped := 0, 0
+	for _, tx := range txs {
+		if err := h.memPool.Add(tx); err != nil {
+			log.Printf("Bỏ giao dịch %s của block bị huỷ: %v", tx.ID(), err)
+			dropped++
+			continue
+		}
+		reinserted++
+	}
+
+	h.pending.Status = PendingAborted
+	h.pending.Reason = reason
+	h.pending.Reinserted = reinserted
+	h.pending.Dropped = dropped
+	h.pending.UpdatedAt = time.Now()
+	log.Printf("Huỷ block %s: %s (trả lại %d, loại %d giao dịch)", h.pending.Block.Hash, reason, reinserted, dropped)
+}
+
+func (h *LeaderHandler) GetPendingBlockHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	if h.pending == nil {
+		http.Error(w, ErrNoPendingBlock.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.pending)
+}
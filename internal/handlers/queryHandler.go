@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/pkg/storage"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Giới hạn số phần tử mỗi trang của các API tra cứu
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// parsePage đọc limit (mặc định defaultPageSize, tối đa maxPageSize) và offset từ query.
+func parsePage(r *http.Request) (offset, limit int, ok bool) {
+	limit = defaultPageSize
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			return 0, 0, false
+		}
+		limit = min(n, maxPageSize)
+	}
+	if raw := r.URL.Query().Get("offset"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return 0, 0, false
+		}
+		offset = n
+	}
+	return offset, limit, true
+}
+
+// GetBlockByHash trả về block theo hash (GET /blocks/{hash}), kể cả block ở nhánh phụ.
+func (h *CommonHandler) GetBlockByHash(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	block, err := h.storageInst.LoadBlock(r.PathValue("hash"))
+	if err == leveldb.ErrNotFound {
+		http.Error(w, "Không tìm thấy block", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Không tải được block", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(block)
+}
+
+// GetBlocks trả về block trên chuỗi chính: GET /blocks?height=N cho một block,
+// GET /blocks?from=N&limit=M cho các block từ height N trở lên.
+func (h *CommonHandler) GetBlocks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	if query.Has("height") {
+		h.GetBlockByHeight(w, r)
+		return
+	}
+
+	from, err := strconv.ParseUint(query.Get("from"), 10, 64)
+	if err != nil {
+		http.Error(w, "Cần height hoặc from hợp lệ", http.StatusBadRequest)
+		return
+	}
+	_, limit, ok := parsePage(r)
+	if !ok {
+		http.Error(w, "limit không hợp lệ", http.StatusBadRequest)
+		return
+	}
+
+	blocks := make([]*blockchain.Block, 0, limit)
+	for height := from; len(blocks) < limit; height++ {
+		block, err := h.storageInst.LoadBlockByHeight(height)
+		if err == leveldb.ErrNotFound {
+			break
+		}
+		if err != nil {
+			http.Error(w, "Không tải được block", http.StatusInternalServerError)
+			return
+		}
+		blocks = append(blocks, block)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(blocks)
+}
+
+type TxResponse struct {
+	storage.TxLocation
+	Confirmations uint64                  `json:"confirmations"`
+	Transaction   *blockchain.Transaction `json:"transaction"`
+}
+
+// txResponse tải giao dịch tại loc; blocks dùng làm cache khi tải nhiều giao dịch cùng block.
+func (h *CommonHandler) txResponse(loc storage.TxLocation, tipHeight uint64, blocks map[string]*blockchain.Block) (*TxResponse, error) {
+	block, ok := blocks[loc.BlockHash]
+	if !ok {
+		var err error
+		if block, err = h.storageInst.LoadBlock(loc.BlockHash); err != nil {
+			return nil, err
+		}
+		blocks[loc.BlockHash] = block
+	}
+	if loc.Index < 0 || loc.Index >= len(block.Transactions) {
+		return nil, leveldb.ErrNotFound
+	}
+	resp := &TxResponse{TxLocation: loc, Transaction: &block.Transactions[loc.Index]}
+	if tipHeight >= loc.Height {
+		resp.Confirmations = tipHeight - loc.Height + 1
+	}
+	return resp, nil
+}
+
+// GetTransaction trả về giao dịch trên chuỗi chính theo hash cùng block chứa nó và vị trí trong block.
+func (h *CommonHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	loc, err := h.storageInst.LoadTxLocation(r.PathValue("hash"))
+	if err == leveldb.ErrNotFound {
+		http.Error(w, "Không tìm thấy giao dịch", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Không tải được giao dịch", http.StatusInternalServerError)
+		return
+	}
+	tip, err := h.storageInst.GetLatestBlock()
+	if err != nil {
+		http.Error(w, "Không tải được block cuối", http.StatusInternalServerError)
+		return
+	}
+	resp, err := h.txResponse(*loc, tip.Header.Height, make(map[string]*blockchain.Block))
+	if err != nil {
+		http.Error(w, "Không tải được giao dịch", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+type TxProofResponse struct {
+	BlockHash string                  `json:"block_hash"`
+	Header    blockchain.BlockHeader  `json:"header"`
+	Proof     *blockchain.MerkleProof `json:"proof"`
+}
+
+// GetTransactionProof trả về Merkle proof của giao dịch cùng header của block chứa nó; client kiểm tra bằng
+// header.VerifyTxProof(proof) mà không cần tải cả block.
+func (h *CommonHandler) GetTransactionProof(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	block, proof, err := h.storageInst.LoadTxProof(r.PathValue("hash"))
+	if err == leveldb.ErrNotFound {
+		http.Error(w, "Không tìm thấy giao dịch", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Không tạo được proof", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(TxProofResponse{BlockHash: block.Hash, Header: block.Header, Proof: proof})
+}
+
+type StateProofResponse struct {
+	BlockHash string                 `json:"block_hash"`
+	Header    blockchain.BlockHeader `json:"header"`
+	Proof     *blockchain.StateProof `json:"proof"`
+}
+
+// GetAddressProof trả về state proof cho số dư và nonce của địa chỉ tại block ?height=N trên chuỗi chính
+// (mặc định là block cuối); client kiểm tra bằng blockchain.VerifyStateProof(proof, header.StateRoot).
+func (h *CommonHandler) GetAddressProof(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var height uint64
+	if raw := r.URL.Query().Get("height"); raw != "" {
+		n, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			http.Error(w, "height không hợp lệ", http.StatusBadRequest)
+			return
+		}
+		height = n
+	} else {
+		tip, err := h.storageInst.GetLatestBlock()
+		if err != nil {
+			http.Error(w, "Không tải được block cuối", http.StatusInternalServerError)
+			return
+		}
+		height = tip.Header.Height
+	}
+
+	block, proof, err := h.storageInst.LoadStateProof(r.PathValue("addr"), height)
+	if err == leveldb.ErrNotFound {
+		http.Error(w, "Không tìm thấy block", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Không tạo được proof", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(StateProofResponse{BlockHash: block.Hash, Header: block.Header, Proof: proof})
+}
+
+// GetAddressTxs trả về giao dịch gửi hoặc nhận của địa chỉ, mới nhất trước, phân trang bằng offset và limit.
+func (h *CommonHandler) GetAddressTxs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Chỉ hỗ trợ GET", http.StatusMethodNotAllowed)
+		return
+	}
+
+	offset, limit, ok := parsePage(r)
+	if !ok {
+		http.Error(w, "offset hoặc limit không hợp lệ", http.StatusBadRequest)
+		return
+	}
+	locs, err := h.storageInst.LoadAddressTxs(r.PathValue("addr"), offset, limit)
+	if err != nil {
+		http.Error(w, "Không tải được danh sách giao dịch", http.StatusInternalServerError)
+		return
+	}
+	tip, err := h.storageInst.GetLatestBlock()
+	if err != nil {
+		http.Error(w, "Không tải được block cuối", http.StatusInternalServerError)
+		return
+	}
+
+	txs := make([]*TxResponse, 0, len(locs))
+	blocks := make(map[string]*blockchain.Block)
+	for _, loc := range locs {
+		resp, err := h.txResponse(loc, tip.Header.Height, blocks)
+		if err != nil {
+			http.Error(w, "Không tải được giao dịch", http.StatusInternalServerError)
+			return
+		}
+		txs = append(txs, resp)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(txs)
+}
@@ -0,0 +1,311 @@
+// Package mempool giữ các giao dịch đã được chấp nhận nhưng chưa vào block.
+package mempool
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+var (
+	ErrDuplicate           = errors.New("giao dịch đã có trong mempool")
+	ErrNonceTooLow         = errors.New("nonce đã được sử dụng")
+	ErrNonceTooHigh        = errors.New("nonce vượt quá xa nonce hiện tại của ví")
+	ErrNonceExists         = errors.New("đã có giao dịch khác cùng nonce đang chờ")
+	ErrPoolFull            = errors.New("mempool đã đầy")
+	ErrAccountFull         = errors.New("ví có quá nhiều giao dịch đang chờ")
+	ErrInsufficientBalance = errors.New("số dư không đủ cho các giao dịch đang chờ")
+)
+
+type Config struct {
+	MaxSize       int           // tổng số giao dịch tối đa
+	MaxPerAccount int           // số giao dịch đang chờ tối đa của một ví
+	TTL           time.Duration // giao dịch chờ quá lâu sẽ bị loại
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxSize:       5000,
+		MaxPerAccount: 64,
+		TTL:           10 * time.Minute,
+	}
+}
+
+type entry struct {
+	tx      blockchain.Transaction
+	id      string
+	size    int
+	addedAt time.Time
+}
+
+// Mempool an toàn khi dùng đồng thời. Giao dịch của mỗi ví được giữ theo thứ tự nonce;
+// Reap chỉ lấy các chuỗi nonce liên tục bắt đầu từ nonce hiện tại của ví và ưu tiên phí cao.
+type Mempool struct {
+	mu       sync.Mutex
+	cfg      Config
+	state    blockchain.StateReader
+	byID     map[string]*entry
+	bySender map[string][]*entry // sắp theo nonce tăng dần
+	now      func() time.Time
+}
+
+func New(state blockchain.StateReader, cfg Config) *Mempool {
+	return &Mempool{
+		cfg:      cfg,
+		state:    state,
+		byID:     make(map[string]*entry),
+		bySender: make(map[string][]*entry),
+		now:      time.Now,
+	}
+}
+
+func txSize(tx *blockchain.Transaction) int {
+	return len(tx.Encode()) + len(tx.Signature)
+}
+
+// Add kiểm tra và thêm giao dịch (chữ ký phải được kiểm tra trước khi gọi).
+func (m *Mempool) Add(tx blockchain.Transaction) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictExpired()
+
+	id := tx.ID()
+	if _, ok := m.byID[id]; ok {
+		return ErrDuplicate
+	}
+
+	acc, err := m.state.GetAccountState(tx.Sender)
+	if err != nil {
+		return err
+	}
+	if tx.Nonce < acc.Nonce {
+		return fmt.Errorf("%w: nonce hiện tại là %d", ErrNonceTooLow, acc.Nonce)
+	}
+	if tx.Nonce >= acc.Nonce+uint64(m.cfg.MaxPerAccount) {
+		return fmt.Errorf("%w: nonce hiện tại là %d", ErrNonceTooHigh, acc.Nonce)
+	}
+
+	queue := m.bySender[tx.Sender]
+	if len(queue) >= m.cfg.MaxPerAccount {
+		return ErrAccountFull
+	}
+
+	total, err := tx.Cost()
+	if err != nil {
+		return err
+	}
+	for _, e := range queue {
+		if e.tx.Nonce == tx.Nonce {
+			return ErrNonceExists
+		}
+		cost, err := e.tx.Cost()
+		if err != nil {
+			return err
+		}
+		if total, err = total.Add(cost); err != nil {
+			return err
+		}
+	}
+	if total > acc.Balance {
+		return ErrInsufficientBalance
+	}
+
+	if len(m.byID) >= m.cfg.MaxSize && !m.evictCheaperThan(tx.Fee) {
+		return ErrPoolFull
+	}
+
+	m.insert(&entry{tx: tx, id: id, size: txSize(&tx), addedAt: m.now()})
+	return nil
+}
+
+func (m *Mempool) insert(e *entry) {
+	queue := append(m.bySender[e.tx.Sender], e)
+	sort.Slice(queue, func(i, j int) bool { return queue[i].tx.Nonce < queue[j].tx.Nonce })
+	m.bySender[e.tx.Sender] = queue
+	m.byID[e.id] = e
+}
+
+func (m *Mempool) remove(e *entry) {
+	delete(m.byID, e.id)
+	queue := m.bySender[e.tx.Sender]
+	for i, other := range queue {
+		if other == e {
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+	}
+	if len(queue) == 0 {
+		delete(m.bySender, e.tx.Sender)
+	} else {
+		m.bySender[e.tx.Sender] = queue
+	}
+}
+
+// evictExpired loại các giao dịch chờ quá TTL cùng mọi giao dịch có nonce lớn hơn của cùng ví,
+// vì sau khoảng trống nonce chúng không bao giờ vào block được mà vẫn chiếm hạn mức của ví.
+func (m *Mempool) evictExpired() {
+	if m.cfg.TTL <= 0 {
+		return
+	}
+	deadline := m.now().Add(-m.cfg.TTL)
+	for _, queue := range m.bySender {
+		for i, e := range queue {
+			if e.addedAt.Before(deadline) {
+				for _, later := range append([]*entry(nil), queue[i:]...) {
+					m.remove(later)
+				}
+				break
+			}
+		}
+	}
+}
+
+// evictCheaperThan loại giao dịch có phí thấp nhất nếu thấp hơn fee. Chỉ xét giao dịch
+// có nonce lớn nhất của mỗi ví để không tạo khoảng trống nonce.
+func (m *Mempool) evictCheaperThan(fee blockchain.Amount) bool {
+	var victim *entry
+	for _, queue := range m.bySender {
+		last := queue[len(queue)-1]
+		if victim == nil || last.tx.Fee < victim.tx.Fee {
+			victim = last
+		}
+	}
+	if victim == nil || victim.tx.Fee >= fee {
+		return false
+	}
+	m.remove(victim)
+	return true
+}
+
+// Reap chọn giao dịch cho block mới mà không xoá khỏi pool: ưu tiên phí cao, giữ đúng
+// thứ tự nonce của từng ví. maxTxs hoặc maxBytes <= 0 nghĩa là không giới hạn.
+func (m *Mempool) Reap(maxTxs, maxBytes int) []blockchain.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.evictExpired()
+
+	var ready txHeap
+	for sender, queue := range m.bySender {
+		acc, err := m.state.GetAccountState(sender)
+		if err != nil {
+			continue
+		}
+		// Chỉ lấy phần liên tục bắt đầu từ nonce hiện tại của ví
+		var runnable []*entry
+		for _, e := range queue {
+			if e.tx.Nonce != acc.Nonce+uint64(len(runnable)) {
+				break
+			}
+			runnable = append(runnable, e)
+		}
+		if len(runnable) > 0 {
+			ready = append(ready, runnable)
+		}
+	}
+	heap.Init(&ready)
+
+	var txs []blockchain.Transaction
+	bytes := 0
+	for ready.Len() > 0 {
+		if maxTxs > 0 && len(txs) >= maxTxs {
+			break
+		}
+		queue := heap.Pop(&ready).([]*entry)
+		head := queue[0]
+		if maxBytes > 0 && bytes+head.size > maxBytes {
+			continue
+		}
+		txs = append(txs, head.tx)
+		bytes += head.size
+		if len(queue) > 1 {
+			heap.Push(&ready, queue[1:])
+		}
+	}
+	return txs
+}
+
+// Remove xoá các giao dịch đã vào block cùng các giao dịch có nonce đã lỗi thời.
+func (m *Mempool) Remove(committed []blockchain.Transaction) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	senders := make(map[string]bool)
+	for i := range committed {
+		if e, ok := m.byID[committed[i].ID()]; ok {
+			m.remove(e)
+		}
+		senders[committed[i].Sender] = true
+	}
+
+	for sender := range senders {
+		acc, err := m.state.GetAccountState(sender)
+		if err != nil {
+			continue
+		}
+		for _, e := range append([]*entry(nil), m.bySender[sender]...) {
+			if e.tx.Nonce < acc.Nonce {
+				m.remove(e)
+			}
+		}
+	}
+}
+
+// Snapshot trả về bản sao các giao dịch đang chờ, theo thứ tự được thêm vào.
+func (m *Mempool) Snapshot() []blockchain.Transaction {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entries := make([]*entry, 0, len(m.byID))
+	for _, e := range m.byID {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].addedAt.Equal(entries[j].addedAt) {
+			return entries[i].addedAt.Before(entries[j].addedAt)
+		}
+		return entries[i].id < entries[j].id
+	})
+
+	txs := make([]blockchain.Transaction, 0, len(entries))
+	for _, e := range entries {
+		txs = append(txs, e.tx)
+	}
+	return txs
+}
+
+func (m *Mempool) Size() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.byID)
+}
+
+// txHeap sắp các hàng đợi theo phí của giao dịch đầu hàng, phí cao lên trước.
+type txHeap [][]*entry
+
+func (h txHeap) Len() int { return len(h) }
+func (h txHeap) Less(i, j int) bool {
+	a, b := h[i][0], h[j][0]
+	if a.tx.Fee != b.tx.Fee {
+		return a.tx.Fee > b.tx.Fee
+	}
+	if !a.addedAt.Equal(b.addedAt) {
+		return a.addedAt.Before(b.addedAt)
+	}
+	return a.id < b.id
+}
+func (h txHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *txHeap) Push(x interface{}) { *h = append(*h, x.([]*entry)) }
+func (h *txHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
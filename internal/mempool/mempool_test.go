@@ -0,0 +1,299 @@
+package mempool
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+)
+
+var (
+	alice = strings.Repeat("a", blockchain.AddressLength)
+	bob   = strings.Repeat("b", blockchain.AddressLength)
+	carol = strings.Repeat("c", blockchain.AddressLength)
+	dave  = strings.Repeat("d", blockchain.AddressLength)
+)
+
+// testState là trạng thái ví trong bộ nhớ; ví chưa có được coi là số dư 1000000, nonce 0.
+type testState struct {
+	mu       sync.Mutex
+	accounts map[string]blockchain.AccountState
+}
+
+func newTestState() *testState {
+	return &testState{accounts: make(map[string]blockchain.AccountState)}
+}
+
+func (s *testState) GetAccountState(address string) (blockchain.AccountState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if acc, ok := s.accounts[address]; ok {
+		return acc, nil
+	}
+	return blockchain.AccountState{Balance: 1000000}, nil
+}
+
+func (s *testState) set(address string, acc blockchain.AccountState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts[address] = acc
+}
+
+func newTx(sender string, nonce uint64, amount, fee blockchain.Amount) blockchain.Transaction {
+	return blockchain.Transaction{
+		Sender:    sender,
+		Receiver:  dave,
+		Amount:    amount,
+		Fee:       fee,
+		Timestamp: 1700000000,
+		Nonce:     nonce,
+	}
+}
+
+// testClock là đồng hồ của mempool trong test, chỉ đổi khi gọi advance.
+type testClock struct{ now time.Time }
+
+func (c *testClock) advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestPool(state blockchain.StateReader, cfg Config) (*Mempool, *testClock) {
+	clock := &testClock{now: time.Unix(1700000000, 0)}
+	m := New(state, cfg)
+	m.now = func() time.Time { return clock.now }
+	return m, clock
+}
+
+func ids(txs []blockchain.Transaction) []string {
+	list := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		list = append(list, tx.ID())
+	}
+	return list
+}
+
+func checkTxs(t *testing.T, name string, got, want []blockchain.Transaction) {
+	t.Helper()
+	if strings.Join(ids(got), ",") != strings.Join(ids(want), ",") {
+		t.Errorf("%s = %s, mong đợi %s", name, describe(got), describe(want))
+	}
+}
+
+// describe in giao dịch dạng người gửi/nonce/phí cho dễ đọc khi test lỗi.
+func describe(txs []blockchain.Transaction) string {
+	var parts []string
+	for _, tx := range txs {
+		parts = append(parts, fmt.Sprintf("%.1s/%d/%s", tx.Sender, tx.Nonce, tx.Fee))
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+func TestAddNonceRules(t *testing.T) {
+	state := newTestState()
+	state.set(alice, blockchain.AccountState{Balance: 100, Nonce: 2})
+	m, _ := newTestPool(state, Config{MaxSize: 100, MaxPerAccount: 4})
+
+	steps := []struct {
+		name string
+		tx   blockchain.Transaction
+		want error
+	}{
+		{"nonce đã dùng", newTx(alice, 1, 10, 0), ErrNonceTooLow},
+		{"nonce quá xa", newTx(alice, 6, 10, 0), ErrNonceTooHigh},
+		{"nonce có khoảng trống vẫn được giữ", newTx(alice, 4, 10, 0), nil},
+		{"nonce hiện tại", newTx(alice, 2, 10, 0), nil},
+		{"gửi lại", newTx(alice, 2, 10, 0), ErrDuplicate},
+		{"cùng nonce khác nội dung", newTx(alice, 2, 20, 0), ErrNonceExists},
+		{"lấp khoảng trống", newTx(alice, 3, 10, 5), nil},
+		{"tổng chi phí vượt số dư", newTx(alice, 5, 66, 0), ErrInsufficientBalance},
+		{"vừa đủ số dư", newTx(alice, 5, 65, 0), nil},
+		{"ví đủ hạn mức", newTx(alice, 5, 1, 0), ErrAccountFull},
+	}
+	for _, step := range steps {
+		if err := m.Add(step.tx); !errors.Is(err, step.want) {
+			t.Errorf("%s: Add = %v, mong đợi %v", step.name, err, step.want)
+		}
+	}
+	if m.Size() != 4 {
+		t.Errorf("Size = %d, mong đợi 4", m.Size())
+	}
+}
+
+func TestReapFeePriorityKeepsNonceOrder(t *testing.T) {
+	state := newTestState()
+	state.set(bob, blockchain.AccountState{Balance: 1000, Nonce: 7})
+	m, clock := newTestPool(state, Config{MaxSize: 100, MaxPerAccount: 10})
+
+	alice0, alice1 := newTx(alice, 0, 10, 1), newTx(alice, 1, 10, 100)
+	bob7 := newTx(bob, 7, 10, 50)
+	carol0 := newTx(carol, 0, 10, 10)
+	carol2 := newTx(carol, 2, 10, 1000) // sau khoảng trống nonce, không bao giờ được lấy
+	dave0 := newTx(dave, 0, 10, 10)
+	for _, tx := range []blockchain.Transaction{alice1, alice0, carol2, bob7, carol0, dave0} {
+		if err := m.Add(tx); err != nil {
+			t.Fatalf("Add %s: %v", describe([]blockchain.Transaction{tx}), err)
+		}
+		// carol0 và dave0 cùng phí: giao dịch thêm trước được lấy trước
+		clock.advance(time.Second)
+	}
+	size := txSize(&alice0)
+
+	tests := []struct {
+		name     string
+		maxTxs   int
+		maxBytes int
+		want     []blockchain.Transaction
+	}{
+		{"không giới hạn", 0, 0, []blockchain.Transaction{bob7, carol0, dave0, alice0, alice1}},
+		{"giới hạn số giao dịch", 2, 0, []blockchain.Transaction{bob7, carol0}},
+		{"giới hạn số byte", 0, 4 * size, []blockchain.Transaction{bob7, carol0, dave0, alice0}},
+		{"giới hạn byte nhỏ hơn một giao dịch", 0, size - 1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkTxs(t, "Reap", m.Reap(tt.maxTxs, tt.maxBytes), tt.want)
+		})
+	}
+	if m.Size() != 6 {
+		t.Errorf("Reap làm đổi kích thước mempool: %d", m.Size())
+	}
+}
+
+func TestExpiredTxEvictsLaterNonces(t *testing.T) {
+	const ttl = 10 * time.Minute
+
+	tests := []struct {
+		name    string
+		expired uint64 // nonce của giao dịch hết hạn trong chuỗi nonce 0, 1, 2 của alice
+		kept    int    // số giao dịch của alice còn lại
+	}{
+		{"giao dịch đầu hết hạn", 0, 0},
+		{"giao dịch giữa hết hạn", 1, 1},
+		{"giao dịch cuối hết hạn", 2, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, clock := newTestPool(newTestState(), Config{MaxSize: 100, MaxPerAccount: 10, TTL: ttl})
+
+			var txs []blockchain.Transaction
+			add := func(tx blockchain.Transaction) {
+				t.Helper()
+				if err := m.Add(tx); err != nil {
+					t.Fatal(err)
+				}
+				clock.advance(time.Second)
+			}
+			// Giao dịch hết hạn được thêm trước, các giao dịch khác thêm sau nửa TTL
+			add(newTx(alice, tt.expired, 10, 0))
+			clock.advance(ttl / 2)
+			for nonce := uint64(0); nonce < 3; nonce++ {
+				tx := newTx(alice, nonce, 10, 0)
+				if nonce != tt.expired {
+					add(tx)
+				}
+				txs = append(txs, tx)
+			}
+			bob0 := newTx(bob, 0, 10, 0)
+			add(bob0)
+
+			clock.advance(ttl/2 + time.Second)
+			want := append(txs[:tt.kept:tt.kept], bob0)
+			checkTxs(t, "Reap", m.Reap(0, 0), want)
+			if m.Size() != len(want) {
+				t.Errorf("Size = %d, mong đợi %d", m.Size(), len(want))
+			}
+		})
+	}
+}
+
+func TestPoolFullEvictsCheapestTail(t *testing.T) {
+	m, _ := newTestPool(newTestState(), Config{MaxSize: 3, MaxPerAccount: 10})
+
+	// Giao dịch đầu của alice rẻ nhất nhưng chỉ giao dịch cuối của mỗi ví mới bị xét loại
+	alice0, alice1 := newTx(alice, 0, 10, 1), newTx(alice, 1, 10, 2)
+	bob0 := newTx(bob, 0, 10, 3)
+	for _, tx := range []blockchain.Transaction{alice0, alice1, bob0} {
+		if err := m.Add(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	carol0, dave0 := newTx(carol, 0, 10, 4), newTx(dave, 0, 10, 5)
+
+	steps := []struct {
+		name string
+		tx   blockchain.Transaction
+		want error
+		pool []blockchain.Transaction // theo thứ tự Reap sau bước này
+	}{
+		{"phí bằng giao dịch cuối rẻ nhất", newTx(carol, 0, 10, 2), ErrPoolFull, []blockchain.Transaction{bob0, alice0, alice1}},
+		{"phí thấp hơn", newTx(carol, 0, 10, 1), ErrPoolFull, []blockchain.Transaction{bob0, alice0, alice1}},
+		{"phí cao hơn loại giao dịch cuối của alice", carol0, nil, []blockchain.Transaction{carol0, bob0, alice0}},
+		{"alice chỉ còn một giao dịch nên bị loại", dave0, nil, []blockchain.Transaction{dave0, carol0, bob0}},
+	}
+	for _, step := range steps {
+		if err := m.Add(step.tx); !errors.Is(err, step.want) {
+			t.Fatalf("%s: Add = %v, mong đợi %v", step.name, err, step.want)
+		}
+		checkTxs(t, step.name, m.Reap(0, 0), step.pool)
+	}
+}
+
+func TestConcurrentAddReap(t *testing.T) {
+	const senders, perSender = 8, 20
+
+	state := newTestState()
+	m, _ := newTestPool(state, Config{MaxSize: senders * perSender, MaxPerAccount: perSender})
+	// Đồng hồ của test không an toàn khi dùng đồng thời
+	m.now = time.Now
+
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		sender := fmt.Sprintf("%064x", i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for nonce := uint64(0); nonce < perSender; nonce++ {
+				if err := m.Add(newTx(sender, nonce, 1, blockchain.Amount(nonce))); err != nil {
+					t.Errorf("Add %.4s/%d: %v", sender, nonce, err)
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	var reapers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		reapers.Add(1)
+		go func() {
+			defer reapers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				// Mỗi ví chỉ được lấy chuỗi nonce liên tục từ 0
+				next := make(map[string]uint64)
+				for _, tx := range m.Reap(50, 0) {
+					if tx.Nonce != next[tx.Sender] {
+						t.Errorf("Reap lấy %.4s/%d, mong đợi nonce %d", tx.Sender, tx.Nonce, next[tx.Sender])
+					}
+					next[tx.Sender]++
+				}
+				m.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	reapers.Wait()
+
+	if m.Size() != senders*perSender {
+		t.Fatalf("Size = %d, mong đợi %d", m.Size(), senders*perSender)
+	}
+	if got := len(m.Reap(0, 0)); got != senders*perSender {
+		t.Errorf("Reap lấy %d giao dịch, mong đợi %d", got, senders*perSender)
+	}
+}
@@ -21,36 +21,91 @@ func GenerateKeyPair() (*ecdsa.PrivateKey, error) {
 	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
 }
 
+// Wallet chỉ chứa thông tin công khai; private key luôn nằm ở phía client.
 type Wallet struct {
-	Address    string
-	PrivateKey []byte
-	PublicKey  []byte
-	Token      int
+	Address   string
+	PublicKey []byte
+	Token     blockchain.Amount
+	Nonce     uint64
 }
 
-func NewWallet(token int) *Wallet {
-	privKey, err := GenerateKeyPair()
-	if err != nil {
-		panic(err)
-	}
-	pubKey := append(privKey.PublicKey.X.Bytes(), privKey.PublicKey.Y.Bytes()...)
-	address := GetAddressFromPubKey(pubKey)
-
+func NewWallet(pubKey []byte, token blockchain.Amount) *Wallet {
 	return &Wallet{
-		PrivateKey: privKey.D.Bytes(),
-		PublicKey:  pubKey,
-		Token:      token,
-		Address:    address,
+		PublicKey: pubKey,
+		Token:     token,
+		Address:   GetAddressFromPubKey(pubKey),
 	}
 }
 
+// MarshalPublicKey mã hoá public key thành X||Y, mỗi toạ độ đủ 32 byte.
+func MarshalPublicKey(pub *ecdsa.PublicKey) []byte {
+	buf := make([]byte, 64)
+	pub.X.FillBytes(buf[:32])
+	pub.Y.FillBytes(buf[32:])
+	return buf
+}
+
+// MarshalPrivateKey trả về D của private key, đủ 32 byte.
+func MarshalPrivateKey(priv *ecdsa.PrivateKey) []byte {
+	return priv.D.FillBytes(make([]byte, 32))
+}
+
 func GetAddressFromPubKey(pubKey []byte) string {
 	hash := sha256.Sum256(pubKey)
 	return hex.EncodeToString(hash[:])
 }
 
 func GenerateSignature(tx *blockchain.Transaction, privateKey *ecdsa.PrivateKey) ([]byte, error) {
-	hash := tx.Hash()
+	return signHash(tx.Hash(), privateKey)
+}
+
+func VerifyTransaction(tx *blockchain.Transaction, publicKey *ecdsa.PublicKey) bool {
+	return verifyHash(tx.Hash(), tx.Signature, publicKey)
+}
+
+// SignVote ký phiếu bầu bằng node key của validator.
+func SignVote(vote *blockchain.Vote, privateKey *ecdsa.PrivateKey) error {
+	sig, err := signHash(vote.SignBytes(), privateKey)
+	if err != nil {
+		return err
+	}
+	vote.Signature = sig
+	return nil
+}
+
+func VerifyVote(vote *blockchain.Vote, publicKey *ecdsa.PublicKey) bool {
+	return verifyHash(vote.SignBytes(), vote.Signature, publicKey)
+}
+
+// SignHeartbeat ký heartbeat bằng node key của leader.
+func SignHeartbeat(hb *blockchain.Heartbeat, privateKey *ecdsa.PrivateKey) error {
+	sig, err := signHash(hb.SignBytes(), privateKey)
+	if err != nil {
+		return err
+	}
+	hb.Signature = sig
+	return nil
+}
+
+func VerifyHeartbeat(hb *blockchain.Heartbeat, publicKey *ecdsa.PublicKey) bool {
+	return verifyHash(hb.SignBytes(), hb.Signature, publicKey)
+}
+
+// SignVoteRequest ký lời xin phiếu bằng node key của ứng viên.
+func SignVoteRequest(req *blockchain.VoteRequest, privateKey *ecdsa.PrivateKey) error {
+	sig, err := signHash(req.SignBytes(), privateKey)
+	if err != nil {
+		return err
+	}
+	req.Signature = sig
+	return nil
+}
+
+func VerifyVoteRequest(req *blockchain.VoteRequest, publicKey *ecdsa.PublicKey) bool {
+	return verifyHash(req.SignBytes(), req.Signature, publicKey)
+}
+
+func signHash(hash []byte, privateKey *ecdsa.PrivateKey) ([]byte, error) {
 	r, s, err := ecdsa.Sign(rand.Reader, privateKey, hash)
 	if err != nil {
 		return nil, err
@@ -58,16 +113,28 @@ func GenerateSignature(tx *blockchain.Transaction, privateKey *ecdsa.PrivateKey)
 	return asn1.Marshal(ECDSASignature{R: r, S: s})
 }
 
-func VerifyTransaction(tx *blockchain.Transaction, publicKey *ecdsa.PublicKey) bool {
+func verifyHash(hash, signature []byte, publicKey *ecdsa.PublicKey) bool {
 	var sig ECDSASignature
-	_, err := asn1.Unmarshal(tx.Signature, &sig)
+	_, err := asn1.Unmarshal(signature, &sig)
 	if err != nil {
 		return false
 	}
-	hash := tx.Hash()
 	return ecdsa.Verify(publicKey, hash, sig.R, sig.S)
 }
 
+// VerifyTransactionSignature kiểm tra public key đi kèm giao dịch thuộc về địa chỉ gửi
+// và chữ ký hợp lệ; dùng làm blockchain.SignatureVerifier.
+func VerifyTransactionSignature(tx *blockchain.Transaction) bool {
+	if GetAddressFromPubKey(tx.PublicKey) != tx.Sender {
+		return false
+	}
+	pubKey, err := ParsePublicKey(tx.PublicKey)
+	if err != nil {
+		return false
+	}
+	return VerifyTransaction(tx, pubKey)
+}
+
 func ParsePublicKey(pubKeyBytes []byte) (*ecdsa.PublicKey, error) {
 	curve := elliptic.P256()
 	keyLen := len(pubKeyBytes) / 2
@@ -77,6 +144,9 @@ func ParsePublicKey(pubKeyBytes []byte) (*ecdsa.PublicKey, error) {
 
 	x := new(big.Int).SetBytes(pubKeyBytes[:keyLen])
 	y := new(big.Int).SetBytes(pubKeyBytes[keyLen:])
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("public key không nằm trên đường cong P-256")
+	}
 
 	return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
 }
@@ -0,0 +1,178 @@
+// Package gossip lan truyền giao dịch giữa các node: giao dịch mới được mempool nhận sẽ được chuyển
+// tiếp cho mọi peer, mỗi giao dịch (theo hash) chỉ được xử lý và chuyển tiếp một lần.
+package gossip
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/mempool"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+)
+
+var (
+	ErrSeen         = errors.New("giao dịch đã được nhận trước đó")
+	ErrBadSignature = errors.New("giao dịch không hợp lệ (sai chữ ký)")
+)
+
+const (
+	relayTimeout = 3 * time.Second
+	relayQueue   = 1024
+)
+
+type Config struct {
+	SeenTTL  time.Duration // thời gian nhớ hash giao dịch đã nhận
+	MaxSeen  int           // số hash tối đa được nhớ
+	MaxRelay int           // số lời gọi chuyển tiếp chạy đồng thời
+}
+
+func DefaultConfig() Config {
+	return Config{SeenTTL: 10 * time.Minute, MaxSeen: 100000, MaxRelay: 16}
+}
+
+// Peers là nguồn peer để chuyển tiếp giao dịch (peers.Manager thoả mãn).
+type Peers interface {
+	Addresses() []string
+}
+
+type relayJob struct {
+	tx   blockchain.Transaction
+	from string
+}
+
+type Gossip struct {
+	nodeID string
+	self   string
+	pool   *mempool.Mempool
+	peers  Peers
+	cfg    Config
+	relay  chan relayJob
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+func New(nodeID, self string, pool *mempool.Mempool, peers Peers, cfg Config) *Gossip {
+	return &Gossip{
+		nodeID: nodeID,
+		self:   self,
+		pool:   pool,
+		peers:  peers,
+		cfg:    cfg,
+		relay:  make(chan relayJob, relayQueue),
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Submit kiểm tra chữ ký, thêm giao dịch vào mempool rồi xếp lịch chuyển tiếp cho các peer.
+// from là địa chỉ gRPC của peer gửi tới (không chuyển tiếp ngược lại peer này), rỗng nếu giao dịch
+// đến từ client qua HTTP hoặc không xác định được peer. Giao dịch đã thấy trong SeenTTL bị bỏ qua với ErrSeen.
+//
+// Chữ ký không nằm trong hash giao dịch, nên chữ ký được kiểm tra trước khi ghi nhận hash: nếu không,
+// một bản sao sai chữ ký gửi tới trước sẽ chặn giao dịch thật trong SeenTTL.
+func (g *Gossip) Submit(tx blockchain.Transaction, from string) error {
+	id := tx.ID()
+	if !network.VerifyTransactionSignature(&tx) {
+		return ErrBadSignature
+	}
+	if !blockchain.ValidAddress(tx.Receiver) {
+		return fmt.Errorf("%w: người nhận %q", blockchain.ErrInvalidAddress, tx.Receiver)
+	}
+	if !g.markSeen(id) {
+		return ErrSeen
+	}
+
+	if err := g.pool.Add(tx); err != nil {
+		// Cho phép nhận lại sau, ví dụ khi nonce trước đó đã vào block
+		g.forget(id)
+		return err
+	}
+
+	select {
+	case g.relay <- relayJob{tx: tx, from: from}:
+	default:
+		log.Printf("Hàng đợi chuyển tiếp đầy, không chuyển tiếp giao dịch %s", id)
+	}
+	return nil
+}
+
+// markSeen ghi nhận hash giao dịch; trả về false nếu đã thấy trong SeenTTL.
+func (g *Gossip) markSeen(id string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+	if at, ok := g.seen[id]; ok && now.Sub(at) < g.cfg.SeenTTL {
+		return false
+	}
+	if len(g.seen) >= g.cfg.MaxSeen {
+		g.pruneSeen(now)
+	}
+	g.seen[id] = now
+	return true
+}
+
+// pruneSeen xoá các hash quá SeenTTL; nếu vẫn đầy thì xoá hash cũ nhất. Người gọi giữ mu.
+func (g *Gossip) pruneSeen(now time.Time) {
+	var (
+		oldestID string
+		oldestAt time.Time
+	)
+	for id, at := range g.seen {
+		if now.Sub(at) >= g.cfg.SeenTTL {
+			delete(g.seen, id)
+			continue
+		}
+		if oldestID == "" || at.Before(oldestAt) {
+			oldestID, oldestAt = id, at
+		}
+	}
+	if len(g.seen) >= g.cfg.MaxSeen && oldestID != "" {
+		delete(g.seen, oldestID)
+	}
+}
+
+func (g *Gossip) forget(id string) {
+	g.mu.Lock()
+	delete(g.seen, id)
+	g.mu.Unlock()
+}
+
+// Run chuyển tiếp các giao dịch đã nhận cho mọi peer (trừ peer gửi tới) cho tới khi ctx bị huỷ.
+func (g *Gossip) Run(ctx context.Context) {
+	sem := make(chan struct{}, g.cfg.MaxRelay)
+	for {
+		var job relayJob
+		select {
+		case <-ctx.Done():
+			return
+		case job = <-g.relay:
+		}
+
+		req := &pb.BroadcastTransactionRequest{
+			Transaction: utils.ConvertToProtoTransaction(&job.tx),
+			NodeID:      g.nodeID,
+			Address:     g.self,
+		}
+		for _, addr := range g.peers.Addresses() {
+			if addr == job.from {
+				continue
+			}
+			sem <- struct{}{}
+			go func(addr string) {
+				defer func() { <-sem }()
+				if _, err := grpcclient.BroadcastTransaction(addr, req, relayTimeout); err != nil {
+					log.Printf("Chuyển tiếp giao dịch %s tới %s thất bại: %v", job.tx.ID(), addr, err)
+				}
+			}(addr)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+)
+
+// Ping kiểm tra peer còn sống và lấy block cuối của peer.
+func Ping(address string, req *pb.PingRequest, timeout time.Duration) (*pb.PingResponse, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return pb.NewDiscoveryClient(conn).Ping(ctx, req)
+}
+
+// GetPeers lấy danh sách peer mà một node đang biết.
+func GetPeers(address string, req *pb.GetPeersRequest, timeout time.Duration) (*pb.GetPeersResponse, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return pb.NewDiscoveryClient(conn).GetPeers(ctx, req)
+}
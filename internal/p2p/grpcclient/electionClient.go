@@ -0,0 +1,36 @@
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+)
+
+// RequestVote xin phiếu bầu leader từ một validator.
+func RequestVote(address string, req *pb.RequestVoteRequest, timeout time.Duration) (*pb.RequestVoteResponse, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return pb.NewProposalServiceClient(conn).RequestVote(ctx, req)
+}
+
+// SendHeartbeat gửi heartbeat của leader tới một validator.
+func SendHeartbeat(address string, req *pb.HeartbeatRequest, timeout time.Duration) (*pb.HeartbeatResponse, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return pb.NewProposalServiceClient(conn).Heartbeat(ctx, req)
+}
@@ -0,0 +1,81 @@
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+)
+
+var genesisHash string
+
+// ErrGenesisMismatch được trả về khi node bên kia dùng genesis khác (kể cả khi node đó từ chối request).
+var ErrGenesisMismatch = errors.New("genesis không khớp")
+
+// SetGenesisHash đặt genesis hash gửi kèm mọi lời gọi gRPC; gọi một lần lúc khởi động.
+func SetGenesisHash(hash string) {
+	genesisHash = hash
+}
+
+// genesisInterceptor gắn genesis hash vào request và từ chối phản hồi từ node có genesis khác.
+func genesisInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx = metadata.AppendToOutgoingContext(ctx, utils.GenesisHashMetadataKey, genesisHash)
+
+	var header metadata.MD
+	if err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Header(&header))...); err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			return fmt.Errorf("%w: %v", ErrGenesisMismatch, err)
+		}
+		return err
+	}
+
+	values := header.Get(utils.GenesisHashMetadataKey)
+	if len(values) == 0 || values[0] != genesisHash {
+		return fmt.Errorf("%w: node %s có genesis khác (%v), từ chối kết nối", ErrGenesisMismatch, cc.Target(), values)
+	}
+	return nil
+}
+
+// genesisStreamInterceptor là bản cho các RPC dạng stream của genesisInterceptor. Header phản hồi
+// chỉ có sau khi request được gửi, nên genesis được kiểm tra ở lần nhận đầu tiên.
+func genesisStreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, utils.GenesisHashMetadataKey, genesisHash)
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &genesisClientStream{ClientStream: stream, target: cc.Target()}, nil
+}
+
+type genesisClientStream struct {
+	grpc.ClientStream
+	target  string
+	checked bool
+}
+
+func (s *genesisClientStream) RecvMsg(m interface{}) error {
+	if !s.checked {
+		header, err := s.Header()
+		if err != nil {
+			return err
+		}
+		values := header.Get(utils.GenesisHashMetadataKey)
+		if len(values) == 0 || values[0] != genesisHash {
+			return fmt.Errorf("%w: node %s có genesis khác (%v), từ chối kết nối", ErrGenesisMismatch, s.target, values)
+		}
+		s.checked = true
+	}
+	return s.ClientStream.RecvMsg(m)
+}
+
+func dial(addr string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts, grpc.WithInsecure(), grpc.WithUnaryInterceptor(genesisInterceptor), grpc.WithStreamInterceptor(genesisStreamInterceptor))
+	return grpc.Dial(addr, opts...)
+}
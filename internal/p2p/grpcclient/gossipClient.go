@@ -0,0 +1,22 @@
+package grpcclient
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+)
+
+// BroadcastTransaction chuyển tiếp một giao dịch cho peer.
+func BroadcastTransaction(address string, req *pb.BroadcastTransactionRequest, timeout time.Duration) (*pb.BroadcastTransactionResponse, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	return pb.NewGossipClient(conn).BroadcastTransaction(ctx, req)
+}
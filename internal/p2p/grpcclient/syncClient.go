@@ -0,0 +1,114 @@
+package grpcclient
+
+import (
+	"context"
+	"io"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+)
+
+// FindCommonAncestor gửi locator tới peer để tìm block chung gần nhất và block cuối của peer.
+func FindCommonAncestor(address string, locator []string) (*pb.LocatorResponse, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return pb.NewProposalServiceClient(conn).FindCommonAncestor(ctx, &pb.LocatorRequest{Hashes: locator})
+}
+
+// StreamBlocks tải các block theo lô từ peer và gọi handle cho từng lô theo thứ tự height.
+// Lô tiếp theo chỉ được đọc khi handle trả về; handle trả lỗi thì stream bị huỷ.
+func StreamBlocks(ctx context.Context, address string, req *pb.StreamBlocksRequest, handle func([]*blockchain.Block) error) error {
+	conn, err := dial(address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := pb.NewProposalServiceClient(conn).StreamBlocks(ctx, req)
+	if err != nil {
+		return err
+	}
+	for {
+		batch, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		blocks := make([]*blockchain.Block, 0, len(batch.Blocks))
+		for _, protoBlk := range batch.Blocks {
+			blocks = append(blocks, utils.ConvertFromProtoBlock(protoBlk))
+		}
+		if err := handle(blocks); err != nil {
+			return err
+		}
+	}
+}
+
+// GetTransactionProof lấy từ peer Merkle proof của giao dịch cùng block chứa nó (chỉ có header).
+func GetTransactionProof(address, txHash string) (*blockchain.Block, *blockchain.MerkleProof, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := pb.NewProposalServiceClient(conn).GetTransactionProof(ctx, &pb.TxProofRequest{TxHash: txHash})
+	if err != nil {
+		return nil, nil, err
+	}
+	block := &blockchain.Block{Header: utils.ConvertFromProtoHeader(resp.Header), Hash: resp.BlockHash}
+	proof := &blockchain.MerkleProof{
+		TxHash:    resp.TxHash,
+		Index:     int(resp.Index),
+		LeafCount: int(resp.LeafCount),
+		Siblings:  resp.Siblings,
+	}
+	return block, proof, nil
+}
+
+// GetAccountProof lấy từ peer state proof của tài khoản tại block height cùng block đó (chỉ có header).
+func GetAccountProof(address, account string, height uint64) (*blockchain.Block, *blockchain.StateProof, error) {
+	conn, err := dial(address)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := pb.NewProposalServiceClient(conn).GetAccountProof(ctx, &pb.AccountProofRequest{Address: account, Height: height})
+	if err != nil {
+		return nil, nil, err
+	}
+	block := &blockchain.Block{Header: utils.ConvertFromProtoHeader(resp.Header), Hash: resp.BlockHash}
+	proof := &blockchain.StateProof{
+		Address:        resp.Address,
+		Siblings:       resp.Siblings,
+		OtherKey:       resp.OtherKey,
+		OtherValueHash: resp.OtherValueHash,
+	}
+	if resp.Exists {
+		proof.Account = &blockchain.AccountState{Balance: blockchain.Amount(resp.Balance), Nonce: resp.Nonce}
+	}
+	return block, proof, nil
+}
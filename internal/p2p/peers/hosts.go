@@ -0,0 +1,124 @@
+package peers
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+)
+
+// Request gọi tới node được nhận diện theo IP của kết nối chứ không theo địa chỉ node gọi tự khai
+// (có thể khai địa chỉ của peer khác), nên điểm và lệnh cấm của chúng được tính theo IP.
+// Nhiều node chung một IP thì chịu chung lệnh cấm.
+type hostScore struct {
+	score       int
+	bannedUntil time.Time
+}
+
+// MisbehavedHost trừ điểm IP đã gửi dữ liệu sai tới node này (ví dụ chuyển tiếp giao dịch sai chữ ký).
+func (m *Manager) MisbehavedHost(host, reason string) {
+	if host == "" {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.hosts[host]
+	if !ok {
+		h = &hostScore{}
+		m.hosts[host] = h
+	}
+	log.Printf("IP %s gửi dữ liệu sai: %s", host, reason)
+	h.score += misbehaveScore
+	if h.score > banScore {
+		return
+	}
+	h.score = 0
+	h.bannedUntil = time.Now().Add(m.cfg.BanDuration)
+	log.Printf("Cấm IP %s tới %s: %s", host, h.bannedUntil.Format(time.RFC3339), reason)
+}
+
+// IsBannedHost cho biết IP có đang bị cấm không; dùng để từ chối request gọi tới từ IP đó.
+func (m *Manager) IsBannedHost(host string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.hosts[host]
+	if !ok {
+		return false
+	}
+	if !h.bannedUntil.IsZero() && time.Now().After(h.bannedUntil) {
+		delete(m.hosts, host)
+		return false
+	}
+	return time.Now().Before(h.bannedUntil)
+}
+
+// resolvedHost là kết quả phân giải DNS của một tên host, dùng lại tới expires. ips rỗng nếu phân giải lỗi.
+type resolvedHost struct {
+	ips     []net.IP
+	expires time.Time
+}
+
+// AddressOfHost cho biết địa chỉ host:port có trỏ tới IP host không, để chỉ nhận địa chỉ do node gọi tới
+// tự khai khi địa chỉ đó đúng là của nó.
+func (m *Manager) AddressOfHost(ctx context.Context, address, host string) bool {
+	addrHost, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	caller := net.ParseIP(host)
+	if caller == nil {
+		return false
+	}
+
+	for _, ip := range m.resolve(ctx, addrHost) {
+		if ip.Equal(caller) || (ip.IsLoopback() && caller.IsLoopback()) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve trả về các IP của tên host. Kết quả (kể cả lỗi) được giữ trong resolveTTL để Ping và
+// BroadcastTransaction gọi tới liên tục không phải chờ DNS mỗi lần; địa chỉ IP không cần phân giải.
+func (m *Manager) resolve(ctx context.Context, name string) []net.IP {
+	if ip := net.ParseIP(name); ip != nil {
+		return []net.IP{ip}
+	}
+	now := time.Now()
+	m.mu.Lock()
+	r, ok := m.resolved[name]
+	m.mu.Unlock()
+	if ok && now.Before(r.expires) {
+		return r.ips
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, rpcTimeout)
+	defer cancel()
+	r = resolvedHost{expires: now.Add(resolveTTL)}
+	if addrs, err := m.lookupIP(ctx, name); err == nil {
+		for _, addr := range addrs {
+			r.ips = append(r.ips, addr.IP)
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.resolved) >= maxResolved {
+		// Tên host do node gọi tự khai nên cache phải có giới hạn: bỏ kết quả hết hạn, vẫn đầy thì bỏ một kết quả bất kỳ
+		for key, old := range m.resolved {
+			if !now.Before(old.expires) {
+				delete(m.resolved, key)
+			}
+		}
+		for key := range m.resolved {
+			if len(m.resolved) < maxResolved {
+				break
+			}
+			delete(m.resolved, key)
+		}
+	}
+	m.resolved[name] = r
+	return r.ips
+}
@@ -0,0 +1,135 @@
+package peers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testStore struct{}
+
+func (testStore) LoadPeers() ([]Peer, error) { return nil, nil }
+func (testStore) SavePeers([]Peer) error     { return nil }
+func (testStore) DeletePeer(string) error    { return nil }
+
+// testResolver trả lời DNS từ bảng trong bộ nhớ và đếm số lần được hỏi.
+type testResolver struct {
+	mu      sync.Mutex
+	hosts   map[string]string
+	lookups int
+}
+
+func (r *testResolver) lookup(_ context.Context, name string) ([]net.IPAddr, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookups++
+	if ip, ok := r.hosts[name]; ok {
+		return []net.IPAddr{{IP: net.ParseIP(ip)}}, nil
+	}
+	return nil, errors.New("không tìm thấy host")
+}
+
+func newTestManager(t *testing.T, seeds ...string) (*Manager, *testResolver) {
+	t.Helper()
+	m, err := New("self", "localhost:50051", seeds, testStore{}, nil, Config{BanDuration: time.Minute, MaxPeers: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver := &testResolver{hosts: map[string]string{"node-a": "10.0.0.1"}}
+	m.lookupIP = resolver.lookup
+	return m, resolver
+}
+
+func TestAddressOfHostCachesLookups(t *testing.T) {
+	m, resolver := newTestManager(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		address string
+		host    string
+		want    bool
+	}{
+		{"node-a:50051", "10.0.0.1", true},
+		{"node-a:50052", "10.0.0.2", false},
+		{"node-b:50051", "10.0.0.1", false},
+		{"10.0.0.3:50051", "10.0.0.3", true},
+		{"127.0.0.1:50051", "::1", true},
+		{"node-a", "10.0.0.1", false},
+		{"node-a:50051", "không phải IP", false},
+	}
+	for round := 0; round < 3; round++ {
+		for _, tt := range tests {
+			if got := m.AddressOfHost(ctx, tt.address, tt.host); got != tt.want {
+				t.Errorf("AddressOfHost(%s, %s) = %v, mong đợi %v", tt.address, tt.host, got, tt.want)
+			}
+		}
+	}
+	// Mỗi tên host chỉ được phân giải một lần, kể cả khi lỗi; địa chỉ IP không cần phân giải
+	if resolver.lookups != 2 {
+		t.Errorf("DNS được hỏi %d lần, mong đợi 2", resolver.lookups)
+	}
+
+	// Hết hạn thì phân giải lại
+	m.mu.Lock()
+	r := m.resolved["node-a"]
+	r.expires = time.Now().Add(-time.Second)
+	m.resolved["node-a"] = r
+	m.mu.Unlock()
+	resolver.hosts["node-a"] = "10.0.0.9"
+	if !m.AddressOfHost(ctx, "node-a:50051", "10.0.0.9") || resolver.lookups != 3 {
+		t.Errorf("kết quả hết hạn không được phân giải lại (%d lần hỏi DNS)", resolver.lookups)
+	}
+}
+
+func TestAddressOfHostCacheBounded(t *testing.T) {
+	m, resolver := newTestManager(t)
+	for i := 0; i < maxResolved+10; i++ {
+		m.AddressOfHost(context.Background(), fmt.Sprintf("host-%d:50051", i), "10.0.0.1")
+	}
+	if len(m.resolved) > maxResolved {
+		t.Errorf("cache giữ %d tên host, tối đa %d", len(m.resolved), maxResolved)
+	}
+	if resolver.lookups != maxResolved+10 {
+		t.Errorf("DNS được hỏi %d lần, mong đợi %d", resolver.lookups, maxResolved+10)
+	}
+}
+
+func TestUnreachableSeedNotBanned(t *testing.T) {
+	m, _ := newTestManager(t, "seed:50051")
+	m.Add("peer:50051", "peer")
+	m.mu.Lock()
+	m.peers["peer:50051"].Score = banScore + 2*-failureScore - 1
+	m.mu.Unlock()
+
+	for i := 0; i < 100; i++ {
+		m.pingFailed("seed:50051", errors.New("connection refused"))
+	}
+	m.pingFailed("peer:50051", errors.New("connection refused"))
+	m.pingFailed("peer:50051", errors.New("connection refused"))
+
+	now := time.Now()
+	byAddress := make(map[string]Peer)
+	for _, p := range m.Peers() {
+		byAddress[p.Address] = p
+	}
+	if seed := byAddress["seed:50051"]; seed.Banned(now) || seed.Score <= banScore {
+		t.Errorf("seed không phản hồi bị cấm: %+v", seed)
+	}
+	if peer := byAddress["peer:50051"]; !peer.Banned(now) {
+		t.Errorf("peer không phải seed không bị cấm: %+v", peer)
+	}
+
+	// Seed vẫn bị cấm khi gửi dữ liệu sai
+	m.Misbehaved("seed:50051", "block sai")
+	m.Misbehaved("seed:50051", "block sai")
+	m.Misbehaved("seed:50051", "block sai")
+	for _, p := range m.Peers() {
+		if p.Address == "seed:50051" && !p.Banned(time.Now()) {
+			t.Errorf("seed gửi dữ liệu sai không bị cấm: %+v", p)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+// Package peers quản lý các node mà node này biết: bắt đầu từ danh sách seed, hỏi thêm peer qua
+// service Discovery, ping định kỳ để biết peer còn sống, chấm điểm và cấm tạm thời peer gửi dữ liệu sai.
+package peers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+)
+
+var ErrBanned = errors.New("peer đang bị cấm")
+
+// Điểm của peer: tăng khi ping thành công, giảm khi lỗi hoặc gửi dữ liệu sai; xuống tới
+// banScore thì peer bị cấm trong BanDuration.
+const (
+	maxScore        = 100
+	pingScore       = 1
+	failureScore    = -2
+	misbehaveScore  = -20
+	banScore        = -50
+	deadFailures    = 3  // số lần ping lỗi liên tiếp để coi peer không còn sống
+	dropFailures    = 20 // số lần ping lỗi liên tiếp để quên peer (trừ seed)
+	rpcTimeout      = 3 * time.Second
+	maxSharedPeers  = 32
+	defaultMaxPeers = 50
+	resolveTTL      = time.Minute // thời gian dùng lại kết quả phân giải DNS của địa chỉ peer tự khai
+	maxResolved     = 1024        // số tên host được giữ kết quả phân giải
+)
+
+type Config struct {
+	PingInterval time.Duration
+	BanDuration  time.Duration
+	MaxPeers     int // số peer tối đa được nhớ, không tính seed
+}
+
+// LoadConfig đọc PEER_PING_INTERVAL, PEER_BAN_DURATION và PEER_MAX.
+func LoadConfig() Config {
+	cfg := Config{PingInterval: 5 * time.Second, BanDuration: 10 * time.Minute, MaxPeers: defaultMaxPeers}
+	if raw := os.Getenv("PEER_PING_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.PingInterval = d
+		} else {
+			log.Printf("PEER_PING_INTERVAL không hợp lệ (%q), dùng %s", raw, cfg.PingInterval)
+		}
+	}
+	if raw := os.Getenv("PEER_BAN_DURATION"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d > 0 {
+			cfg.BanDuration = d
+		} else {
+			log.Printf("PEER_BAN_DURATION không hợp lệ (%q), dùng %s", raw, cfg.BanDuration)
+		}
+	}
+	if raw := os.Getenv("PEER_MAX"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			cfg.MaxPeers = n
+		} else {
+			log.Printf("PEER_MAX không hợp lệ (%q), dùng %d", raw, cfg.MaxPeers)
+		}
+	}
+	return cfg
+}
+
+// ParseSeeds đọc danh sách địa chỉ gRPC dạng "host:port,host:port".
+func ParseSeeds(raw string) []string {
+	var seeds []string
+	for _, addr := range strings.Split(raw, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			seeds = append(seeds, addr)
+		}
+	}
+	return seeds
+}
+
+type Peer struct {
+	Address     string    `json:"address"`
+	NodeID      string    `json:"node_id,omitempty"`
+	Seed        bool      `json:"seed"`
+	Score       int       `json:"score"`
+	Height      uint64    `json:"height"`
+	TipHash     string    `json:"tip_hash,omitempty"`
+	LastSeen    time.Time `json:"last_seen"`
+	Failures    int       `json:"failures"`
+	LastError   string    `json:"last_error,omitempty"`
+	BannedUntil time.Time `json:"banned_until"`
+	BanReason   string    `json:"ban_reason,omitempty"`
+}
+
+// Alive cho biết peer đã từng trả lời ping và chưa lỗi liên tiếp quá deadFailures lần.
+func (p *Peer) Alive() bool {
+	return !p.LastSeen.IsZero() && p.Failures < deadFailures
+}
+
+func (p *Peer) Banned(now time.Time) bool {
+	return now.Before(p.BannedUntil)
+}
+
+// Store lưu danh sách peer để node nhớ peer sau khi khởi động lại (storage.Storage thoả mãn).
+type Store interface {
+	LoadPeers() ([]Peer, error)
+	SavePeers(peers []Peer) error
+	DeletePeer(address string) error
+}
+
+// Chain cung cấp block cuối của node để gửi kèm ping.
+type Chain interface {
+	GetLatestBlock() (*blockchain.Block, error)
+}
+
+type Manager struct {
+	nodeID string
+	self   string // địa chỉ gRPC mà node này quảng bá
+	store  Store
+	chain  Chain
+	cfg    Config
+
+	lookupIP func(ctx context.Context, host string) ([]net.IPAddr, error)
+
+	mu       sync.Mutex
+	peers    map[string]*Peer
+	hosts    map[string]*hostScore
+	resolved map[string]resolvedHost
+}
+
+// New tạo manager từ các peer đã lưu cùng danh sách seed (seed không bao giờ bị quên).
+func New(nodeID, self string, seeds []string, store Store, chain Chain, cfg Config) (*Manager, error) {
+	m := &Manager{
+		nodeID:   nodeID,
+		self:     self,
+		store:    store,
+		chain:    chain,
+		cfg:      cfg,
+		lookupIP: net.DefaultResolver.LookupIPAddr,
+		peers:    make(map[string]*Peer),
+		hosts:    make(map[string]*hostScore),
+		resolved: make(map[string]resolvedHost),
+	}
+	stored, err := store.LoadPeers()
+	if err != nil {
+		return nil, err
+	}
+	for i := range stored {
+		p := stored[i]
+		p.Seed = false
+		m.peers[p.Address] = &p
+	}
+	for _, addr := range seeds {
+		if addr == self {
+			continue
+		}
+		if p, ok := m.peers[addr]; ok {
+			p.Seed = true
+			continue
+		}
+		m.peers[addr] = &Peer{Address: addr, Seed: true}
+	}
+	return m, nil
+}
+
+// Add ghi nhận một địa chỉ peer mới; trả về false nếu bỏ qua (chính node này, đã biết, hoặc đã đủ peer).
+func (m *Manager) Add(address, nodeID string) bool {
+	if address == "" || address == m.self || nodeID == m.nodeID {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if p, ok := m.peers[address]; ok {
+		if nodeID != "" {
+			p.NodeID = nodeID
+		}
+		return false
+	}
+	if m.countNonSeed() >= m.cfg.MaxPeers {
+		return false
+	}
+	m.peers[address] = &Peer{Address: address, NodeID: nodeID}
+	log.Printf("Biết thêm peer %s (%s)", address, nodeID)
+	return true
+}
+
+func (m *Manager) countNonSeed() int {
+	n := 0
+	for _, p := range m.peers {
+		if !p.Seed {
+			n++
+		}
+	}
+	return n
+}
+
+// Run ping các peer và trao đổi danh sách peer mỗi PingInterval cho tới khi ctx bị huỷ.
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.PingInterval)
+	defer ticker.Stop()
+	for {
+		m.round()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// round ping song song mọi peer không bị cấm; peer trả lời được hỏi thêm danh sách peer của nó.
+func (m *Manager) round() {
+	var height uint64
+	if tip, err := m.chain.GetLatestBlock(); err == nil {
+		height = tip.Header.Height
+	}
+
+	now := time.Now()
+	var targets []string
+	m.mu.Lock()
+	for addr, p := range m.peers {
+		if !p.Banned(now) {
+			targets = append(targets, addr)
+		}
+	}
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, addr := range targets {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			resp, err := grpcclient.Ping(addr, &pb.PingRequest{NodeID: m.nodeID, Address: m.self, Height: height}, rpcTimeout)
+			if err != nil {
+				m.pingFailed(addr, err)
+				return
+			}
+			m.pingSucceeded(addr, resp)
+
+			peers, err := grpcclient.GetPeers(addr, &pb.GetPeersRequest{NodeID: m.nodeID, Address: m.self}, rpcTimeout)
+			if err != nil {
+				return
+			}
+			for _, info := range peers.Peers {
+				m.Add(info.Address, info.NodeID)
+			}
+		}(addr)
+	}
+	wg.Wait()
+
+	if err := m.store.SavePeers(m.Peers()); err != nil {
+		log.Printf("Lưu danh sách peer thất bại: %v", err)
+	}
+}
+
+func (m *Manager) pingSucceeded(addr string, resp *pb.PingResponse) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.peers[addr]
+	if !ok {
+		return
+	}
+	if resp.NodeID == m.nodeID {
+		// Địa chỉ trỏ về chính node này (ví dụ seed trùng địa chỉ của node)
+		delete(m.peers, addr)
+		return
+	}
+	if !p.Alive() {
+		log.Printf("Peer %s (%s) đang hoạt động, height %d", addr, resp.NodeID, resp.Height)
+	}
+	p.NodeID = resp.NodeID
+	p.Height = resp.Height
+	p.TipHash = resp.TipHash
+	p.LastSeen = time.Now()
+	p.Failures = 0
+	p.LastError = ""
+	p.Score = min(p.Score+pingScore, maxScore)
+}
+
+func (m *Manager) pingFailed(addr string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	p, ok := m.peers[addr]
+	if !ok {
+		return
+	}
+	p.Failures++
+	p.LastError = err.Error()
+	if p.Failures == deadFailures && !p.LastSeen.IsZero() {
+		log.Printf("Peer %s không phản hồi: %v", addr, err)
+	}
+	if errors.Is(err, grpcclient.ErrGenesisMismatch) {
+		m.adjustScore(p, misbehaveScore, err.Error())
+	} else if !p.Seed || p.Score+failureScore > banScore {
+		// Seed không phản hồi không bị cấm, để node nhận ra ngay khi seed hoạt động trở lại
+		m.adjustScore(p, failureScore, "")
+	}
+	if !p.Seed && p.Failures >= dropFailures {
+		delete(m.peers, addr)
+		if err := m.store.DeletePeer(addr); err != nil {
+			log.Printf("Xoá peer %s thất bại: %v", addr, err)
+		}
+		log.Printf("Quên peer %s sau %d lần ping lỗi", addr, p.Failures)
+	}
+}
+
+// Misbehaved trừ điểm peer gửi dữ liệu sai (block hoặc header không hợp lệ, genesis khác...).
+func (m *Manager) Misbehaved(address, reason string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if p, ok := m.peers[address]; ok {
+		log.Printf("Peer %s gửi dữ liệu sai: %s", address, reason)
+		m.adjustScore(p, misbehaveScore, reason)
+	}
+}
+
+// adjustScore cộng delta vào điểm của peer và cấm peer nếu điểm xuống tới banScore. Người gọi giữ mu.
+func (m *Manager) adjustScore(p *Peer, delta int, reason string) {
+	p.Score += delta
+	if p.Score > banScore {
+		return
+	}
+	if reason == "" {
+		reason = p.LastError
+	}
+	p.BannedUntil = time.Now().Add(m.cfg.BanDuration)
+	p.BanReason = reason
+	p.Score = 0
+	log.Printf("Cấm peer %s tới %s: %s", p.Address, p.BannedUntil.Format(time.RFC3339), reason)
+}
+
+// Addresses trả về địa chỉ các peer còn sống và không bị cấm, điểm cao trước.
+func (m *Manager) Addresses() []string {
+	var addrs []string
+	for _, p := range m.usable() {
+		addrs = append(addrs, p.Address)
+	}
+	return addrs
+}
+
+// Best trả về peer còn sống có height cao nhất.
+func (m *Manager) Best() (Peer, bool) {
+	var best Peer
+	found := false
+	for _, p := range m.usable() {
+		if !found || p.Height > best.Height {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
+
+// Shared là các peer gửi cho node khác qua GetPeers.
+func (m *Manager) Shared() []Peer {
+	peers := m.usable()
+	if len(peers) > maxSharedPeers {
+		peers = peers[:maxSharedPeers]
+	}
+	return peers
+}
+
+func (m *Manager) usable() []Peer {
+	now := time.Now()
+	var peers []Peer
+	m.mu.Lock()
+	for _, p := range m.peers {
+		if p.Alive() && !p.Banned(now) {
+			peers = append(peers, *p)
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(peers, func(i, j int) bool {
+		if peers[i].Score != peers[j].Score {
+			return peers[i].Score > peers[j].Score
+		}
+		return peers[i].Address < peers[j].Address
+	})
+	return peers
+}
+
+// Peers trả về bản sao mọi peer đang biết, theo địa chỉ.
+func (m *Manager) Peers() []Peer {
+	m.mu.Lock()
+	peers := make([]Peer, 0, len(m.peers))
+	for _, p := range m.peers {
+		peers = append(peers, *p)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(peers, func(i, j int) bool { return peers[i].Address < peers[j].Address })
+	return peers
+}
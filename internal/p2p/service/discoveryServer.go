@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/peers"
+	"github.com/chauduongphattien/golang-chain/pkg/storage"
+)
+
+// DiscoveryServer trả lời ping và chia sẻ danh sách peer; node gọi tới được ghi nhận làm peer.
+type DiscoveryServer struct {
+	pb.UnimplementedDiscoveryServer
+	Storage *storage.Storage
+	Peers   *peers.Manager
+	NodeID  string
+}
+
+func NewDiscoveryServer(store *storage.Storage, manager *peers.Manager, nodeID string) *DiscoveryServer {
+	return &DiscoveryServer{
+		Storage: store,
+		Peers:   manager,
+		NodeID:  nodeID,
+	}
+}
+
+// callerHost trả về IP của node gọi tới theo kết nối gRPC; đây là định danh duy nhất không bị giả
+// được của request, khác với địa chỉ và node ID mà node gọi tự khai.
+func callerHost(ctx context.Context) (string, error) {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "", status.Error(codes.Internal, "không xác định được địa chỉ kết nối")
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String(), nil
+	}
+	return host, nil
+}
+
+// Ping trả lời trạng thái của node. Địa chỉ node gọi tự khai chỉ được ghi nhận làm peer khi nó trỏ tới
+// IP của kết nối.
+func (s *DiscoveryServer) Ping(ctx context.Context, req *pb.PingRequest) (*pb.PingResponse, error) {
+	host, err := callerHost(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Peers.IsBannedHost(host) {
+		return nil, status.Error(codes.PermissionDenied, peers.ErrBanned.Error())
+	}
+	if s.Peers.AddressOfHost(ctx, req.Address, host) {
+		s.Peers.Add(req.Address, req.NodeID)
+	}
+
+	tip, err := s.Storage.GetLatestBlock()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "không tải được block cuối: %v", err)
+	}
+	return &pb.PingResponse{NodeID: s.NodeID, Height: tip.Header.Height, TipHash: tip.Hash}, nil
+}
+
+func (s *DiscoveryServer) GetPeers(ctx context.Context, req *pb.GetPeersRequest) (*pb.GetPeersResponse, error) {
+	host, err := callerHost(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Peers.IsBannedHost(host) {
+		return nil, status.Error(codes.PermissionDenied, peers.ErrBanned.Error())
+	}
+
+	resp := &pb.GetPeersResponse{}
+	for _, p := range s.Peers.Shared() {
+		if p.Address == req.Address {
+			continue
+		}
+		resp.Peers = append(resp.Peers, &pb.PeerInfo{Address: p.Address, NodeID: p.NodeID})
+	}
+	return resp, nil
+}
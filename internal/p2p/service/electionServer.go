@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+)
+
+var errNoElection = status.Error(codes.Unimplemented, "node không dùng bầu chọn leader")
+
+func (s *ProposalServer) RequestVote(ctx context.Context, req *pb.RequestVoteRequest) (*pb.RequestVoteResponse, error) {
+	if s.Election == nil {
+		return nil, errNoElection
+	}
+	return s.Election.HandleRequestVote(req), nil
+}
+
+func (s *ProposalServer) Heartbeat(ctx context.Context, req *pb.HeartbeatRequest) (*pb.HeartbeatResponse, error) {
+	if s.Election == nil {
+		return nil, errNoElection
+	}
+	return s.Election.HandleHeartbeat(req), nil
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+)
+
+// GenesisUnaryInterceptor từ chối mọi request từ node có genesis khác node này,
+// đồng thời gửi genesis hash của node trong header phản hồi.
+func GenesisUnaryInterceptor(genesisHash string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		grpc.SetHeader(ctx, metadata.Pairs(utils.GenesisHashMetadataKey, genesisHash))
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(utils.GenesisHashMetadataKey)
+		if len(values) == 0 || values[0] != genesisHash {
+			return nil, status.Errorf(codes.FailedPrecondition, "genesis không khớp: node này dùng %s", genesisHash)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// GenesisStreamInterceptor là bản cho các RPC dạng stream của GenesisUnaryInterceptor.
+func GenesisStreamInterceptor(genesisHash string) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ss.SetHeader(metadata.Pairs(utils.GenesisHashMetadataKey, genesisHash))
+
+		md, _ := metadata.FromIncomingContext(ss.Context())
+		values := md.Get(utils.GenesisHashMetadataKey)
+		if len(values) == 0 || values[0] != genesisHash {
+			return status.Errorf(codes.FailedPrecondition, "genesis không khớp: node này dùng %s", genesisHash)
+		}
+		return handler(srv, ss)
+	}
+}
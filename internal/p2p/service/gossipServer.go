@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/gossip"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/peers"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+)
+
+// GossipServer nhận giao dịch do peer chuyển tiếp và đưa vào mempool qua gossip.Gossip.
+type GossipServer struct {
+	pb.UnimplementedGossipServer
+	Gossip *gossip.Gossip
+	Peers  *peers.Manager
+}
+
+func NewGossipServer(g *gossip.Gossip, manager *peers.Manager) *GossipServer {
+	return &GossipServer{
+		Gossip: g,
+		Peers:  manager,
+	}
+}
+
+// BroadcastTransaction nhận giao dịch chuyển tiếp; peer gửi giao dịch sai chữ ký bị trừ điểm theo IP của kết nối.
+func (s *GossipServer) BroadcastTransaction(ctx context.Context, req *pb.BroadcastTransactionRequest) (*pb.BroadcastTransactionResponse, error) {
+	host, err := callerHost(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if s.Peers.IsBannedHost(host) {
+		return nil, status.Error(codes.PermissionDenied, peers.ErrBanned.Error())
+	}
+	if req.Transaction == nil {
+		return nil, status.Error(codes.InvalidArgument, "thiếu giao dịch")
+	}
+
+	// Địa chỉ tự khai chỉ dùng để không chuyển tiếp ngược lại, và chỉ khi nó trỏ tới IP của kết nối
+	from := ""
+	if s.Peers.AddressOfHost(ctx, req.Address, host) {
+		from = req.Address
+	}
+	tx := utils.ConvertFromProtoTransaction(req.Transaction)
+	if err := s.Gossip.Submit(tx, from); err != nil {
+		if errors.Is(err, gossip.ErrBadSignature) || errors.Is(err, blockchain.ErrInvalidAddress) {
+			s.Peers.MisbehavedHost(host, "chuyển tiếp giao dịch không hợp lệ "+tx.ID()+": "+err.Error())
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return &pb.BroadcastTransactionResponse{Accepted: false, Message: err.Error()}, nil
+	}
+	return &pb.BroadcastTransactionResponse{Accepted: true, Message: "Giao dịch đã được nhận"}, nil
+}
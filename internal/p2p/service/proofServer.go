@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// GetTransactionProof trả về Merkle proof của giao dịch trên chuỗi chính cùng header của block chứa nó.
+func (s *ProposalServer) GetTransactionProof(ctx context.Context, req *pb.TxProofRequest) (*pb.TxProofResponse, error) {
+	block, proof, err := s.Storage.LoadTxProof(req.TxHash)
+	if err == leveldb.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "không tìm thấy giao dịch %s", req.TxHash)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "không tạo được proof: %v", err)
+	}
+
+	return &pb.TxProofResponse{
+		BlockHash: block.Hash,
+		Header:    utils.ConvertToProtoHeader(&block.Header),
+		TxHash:    proof.TxHash,
+		Index:     uint32(proof.Index),
+		LeafCount: uint32(proof.LeafCount),
+		Siblings:  proof.Siblings,
+	}, nil
+}
+
+// GetAccountProof trả về state proof của tài khoản tại block height trên chuỗi chính cùng header của block đó.
+func (s *ProposalServer) GetAccountProof(ctx context.Context, req *pb.AccountProofRequest) (*pb.AccountProofResponse, error) {
+	block, proof, err := s.Storage.LoadStateProof(req.Address, req.Height)
+	if err == leveldb.ErrNotFound {
+		return nil, status.Errorf(codes.NotFound, "không có block %d trên chuỗi chính", req.Height)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "không tạo được proof: %v", err)
+	}
+
+	resp := &pb.AccountProofResponse{
+		BlockHash:      block.Hash,
+		Header:         utils.ConvertToProtoHeader(&block.Header),
+		Address:        proof.Address,
+		Siblings:       proof.Siblings,
+		OtherKey:       proof.OtherKey,
+		OtherValueHash: proof.OtherValueHash,
+	}
+	if proof.Account != nil {
+		resp.Exists = true
+		resp.Balance = uint64(proof.Account.Balance)
+		resp.Nonce = proof.Account.Nonce
+	}
+	return resp, nil
+}
@@ -2,25 +2,40 @@ package service
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"log"
 
-	"github.com/syndtr/goleveldb/leveldb"
-
 	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
 
 	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/election"
+	"github.com/chauduongphattien/golang-chain/internal/network"
 	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+	"github.com/chauduongphattien/golang-chain/internal/syncer"
 	"github.com/chauduongphattien/golang-chain/pkg/storage"
+	"github.com/syndtr/goleveldb/leveldb"
 )
 
 type ProposalServer struct {
 	pb.UnimplementedProposalServiceServer
-	Storage *storage.Storage
+	Storage   *storage.Storage
+	Validator *blockchain.Validator
+	Engine    consensus.Engine
+	// Election chỉ có khi dùng engine bỏ phiếu; nil thì các RPC bầu chọn bị từ chối
+	Election *election.Election
+	// Syncer được đánh thức khi nhận block chưa có block cha; có thể nil
+	Syncer *syncer.Syncer
 }
 
-func NewProposalServer(store *storage.Storage) *ProposalServer {
-	return &ProposalServer{Storage: store}
+func NewProposalServer(store *storage.Storage, engine consensus.Engine, elect *election.Election, sync *syncer.Syncer) *ProposalServer {
+	return &ProposalServer{
+		Storage:   store,
+		Validator: blockchain.NewValidator(store, store, network.VerifyTransactionSignature),
+		Engine:    engine,
+		Election:  elect,
+		Syncer:    sync,
+	}
 }
 
 func (s *ProposalServer) SendProposal(ctx context.Context, req *pb.ProposalRequest) (*pb.ProposalResponse, error) {
@@ -30,63 +45,88 @@ func (s *ProposalServer) SendProposal(ctx context.Context, req *pb.ProposalReque
 	block := utils.ConvertFromProtoBlock(req.Block)
 
 	log.Printf("Thông tin block nhận được:\n"+
+		"  Height: %d\n"+
 		"  Timestamp: %d\n"+
 		"  PrevHash: %s\n"+
 		"  Hash: %s\n"+
 		"  MerkleRoot: %s\n"+
-		"  Transactions:\n", block.Timestamp, block.PrevHash, block.Hash, block.MerkleRoot)
+		"  Transactions:\n", block.Header.Height, block.Header.Timestamp, block.Header.PrevHash, block.Hash, block.Header.MerkleRoot)
 
 	for i, tx := range block.Transactions {
-		log.Printf("    Tx #%d - Sender: %s, Receiver: %s, Amount: %.6f, Timestamp: %d, Signature: %s", i+1, tx.Sender, tx.Receiver, tx.Amount, tx.Timestamp, tx.Signature)
+		log.Printf("    Tx #%d - Sender: %s, Receiver: %s, Amount: %s, Timestamp: %d, Signature: %s", i+1, tx.Sender, tx.Receiver, tx.Amount, tx.Timestamp, tx.Signature)
 	}
 
-	calculatedRoot := blockchain.CalculateMerkleRoot(block.Transactions)
-	if calculatedRoot != block.MerkleRoot {
+	if _, err := s.validate(block); err != nil {
+		log.Println("Block không hợp lệ:", err)
 		return &pb.ProposalResponse{
-			Message:  "Merkle Root không khớp",
+			Message:  err.Error(),
 			Accepted: false,
+			Reason:   string(blockchain.RejectReasonOf(err)),
 		}, nil
 	}
 
-	lastBlock, errLoadLatesBl := s.Storage.GetLatestBlock()
-
-	if errLoadLatesBl != nil && errLoadLatesBl != leveldb.ErrNotFound {
-		log.Println("Lỗi khi load block cuối cùng:", errLoadLatesBl)
-
+	vote, err := s.Engine.Vote(block, consensus.ProposalInfo{LeaderID: req.LeaderID, Term: req.Term, Round: req.Round})
+	if err != nil {
+		log.Println("Từ chối proposal:", err)
 		return &pb.ProposalResponse{
-			Message:  "Khong the load block cuoi",
+			Message:  err.Error(),
 			Accepted: false,
+			Reason:   string(blockchain.RejectReasonOf(err)),
 		}, nil
 	}
 
-	if lastBlock != nil {
-		if block.PrevHash != lastBlock.Hash {
-			return &pb.ProposalResponse{
-				Message:  "Block không nối tiếp đúng",
-				Accepted: false,
-			}, nil
-		}
-	}
-
 	return &pb.ProposalResponse{
 		Message:  "Block hợp lệ, đã nhận",
 		Accepted: true,
+		Vote:     utils.ConvertToProtoVote(&vote),
 	}, nil
 }
 
+// validate kiểm tra block với block cuối hiện tại làm block cha và trả về block cha đó.
+func (s *ProposalServer) validate(block *blockchain.Block) (*blockchain.Block, error) {
+	lastBlock, err := s.Storage.GetLatestBlock()
+	if err != nil {
+		log.Println("Lỗi khi load block cuối cùng:", err)
+		return nil, &blockchain.ValidationError{Reason: blockchain.ReasonInternal, Message: "Không thể load block cuối"}
+	}
+	return lastBlock, s.Validator.ValidateBlock(block, lastBlock)
+}
+
 // cu ly commit block
 func (s *ProposalServer) CommitBlock(ctx context.Context, req *pb.CommitBlockRequest) (*pb.CommitBlockResponse, error) {
 	block := utils.ConvertFromProtoBlock(req.Block)
+	block.Certificate = utils.ConvertFromProtoCertificate(req.Certificate)
+
+	parent, err := s.validateCommit(block)
+	if err == nil {
+		err = s.Engine.Validate(block, parent)
+	}
+	if err != nil {
+		log.Println("Từ chối commit block không hợp lệ:", err)
+		return &pb.CommitBlockResponse{
+			Message: err.Error(),
+			Success: false,
+			Reason:  string(blockchain.RejectReasonOf(err)),
+		}, nil
+	}
 
-	err := s.Storage.SaveBlock(block)
+	update, err := s.Storage.AddBlock(block)
+	if errors.Is(err, storage.ErrKnownBlock) {
+		return &pb.CommitBlockResponse{Message: "Block đã có", Success: true}, nil
+	}
 	if err != nil {
 		log.Println("Lỗi khi commit block:", err)
 		return &pb.CommitBlockResponse{
-			Message: "Commit thất bại",
+			Message: "Commit thất bại: " + err.Error(),
 			Success: false,
+			Reason:  string(blockchain.RejectReasonOf(err)),
 		}, nil
 	}
 
+	if update == nil {
+		log.Println("Block được lưu ở nhánh phụ (Commit)")
+		return &pb.CommitBlockResponse{Message: "Block được lưu ở nhánh phụ", Success: true}, nil
+	}
 	log.Println("Block đã được lưu vào local storage (Commit)")
 	return &pb.CommitBlockResponse{
 		Message: "Commit thành công",
@@ -94,34 +134,28 @@ func (s *ProposalServer) CommitBlock(ctx context.Context, req *pb.CommitBlockReq
 	}, nil
 }
 
-// xu ly dong bo Block
-func (s *ProposalServer) SyncMissingBlocks(ctx context.Context, req *pb.SyncBlocksRequest) (*pb.SyncBlocksResponse, error) {
-	knownHash := req.FromHash
-	var blocks []*pb.Block
-
-	current, err := s.Storage.GetLatestBlock()
+// validateCommit kiểm tra block được commit với block cha của nó. Block nối tiếp block cuối được
+// kiểm tra đầy đủ; block thuộc nhánh khác chưa kiểm tra được nonce và số dư (storage kiểm tra khi đổi nhánh).
+func (s *ProposalServer) validateCommit(block *blockchain.Block) (*blockchain.Block, error) {
+	parent, err := s.Storage.LoadBlock(block.Header.PrevHash)
+	if err == leveldb.ErrNotFound {
+		if s.Syncer != nil {
+			s.Syncer.Trigger()
+		}
+		return nil, &blockchain.ValidationError{
+			Reason:  blockchain.ReasonBadParent,
+			Message: "chưa có block cha " + block.Header.PrevHash + ", cần đồng bộ",
+		}
+	}
 	if err != nil {
-		log.Printf("Lỗi lấy block cuối: %v", err)
-		return nil, err
+		return nil, &blockchain.ValidationError{Reason: blockchain.ReasonInternal, Message: "Không thể load block cha"}
 	}
-
-	log.Printf("cur block: %x\n", current.Hash)
-	log.Printf("knownHash: %x\n", knownHash)
-
-	for current != nil && current.Hash != knownHash {
-		protoBlk := utils.ConvertToProtoBlock(current)
-		blocks = append([]*pb.Block{protoBlk}, blocks...) // prepend
-
-		current, err = s.Storage.LoadBlock(current.PrevHash)
-		if err != nil {
-			log.Printf("Lỗi load block theo prevHash: %v", err)
-			break
-		}
+	lastBlock, err := s.Storage.GetLatestBlock()
+	if err != nil {
+		return nil, &blockchain.ValidationError{Reason: blockchain.ReasonInternal, Message: "Không thể load block cuối"}
 	}
-
-	if current == nil || current.Hash != knownHash {
-		return nil, fmt.Errorf("Không tìm thấy block có hash %x", knownHash)
+	if parent.Hash == lastBlock.Hash {
+		return parent, s.Validator.ValidateBlock(block, parent)
 	}
-
-	return &pb.SyncBlocksResponse{Blocks: blocks}, nil
+	return parent, s.Validator.ValidateBlockStructure(block, parent)
 }
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"log"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Giới hạn của mỗi lô trong StreamBlocks. Kích thước lô nhỏ hơn nhiều giới hạn 4MB của gRPC;
+// stream chờ client đọc xong (flow control của HTTP/2) trước khi gửi tiếp.
+const (
+	defaultStreamBatch = 64
+	maxStreamBatch     = 512
+	maxStreamBatchSize = 1 << 20
+)
+
+// FindCommonAncestor trả về block đầu tiên trong locator nằm trên chuỗi chính của node này.
+func (s *ProposalServer) FindCommonAncestor(ctx context.Context, req *pb.LocatorRequest) (*pb.LocatorResponse, error) {
+	tip, err := s.Storage.GetLatestBlock()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "không tải được block cuối: %v", err)
+	}
+	resp := &pb.LocatorResponse{TipHeight: tip.Header.Height, TipHash: tip.Hash}
+
+	for _, hash := range req.Hashes {
+		meta, err := s.Storage.LoadBlockMeta(hash)
+		if err != nil {
+			continue
+		}
+		canonical, err := s.Storage.LoadBlockByHeight(meta.Height)
+		if err != nil || canonical.Hash != hash {
+			continue
+		}
+		resp.Found = true
+		resp.Height = meta.Height
+		resp.Hash = hash
+		break
+	}
+	return resp, nil
+}
+
+// StreamBlocks gửi các block trên chuỗi chính từ fromHeight tới toHeight (0: tới block cuối) theo lô.
+func (s *ProposalServer) StreamBlocks(req *pb.StreamBlocksRequest, stream pb.ProposalService_StreamBlocksServer) error {
+	tip, err := s.Storage.GetLatestBlock()
+	if err != nil {
+		return status.Errorf(codes.Internal, "không tải được block cuối: %v", err)
+	}
+	to := req.ToHeight
+	if to == 0 || to > tip.Header.Height {
+		to = tip.Header.Height
+	}
+	if req.FromHeight > to {
+		return status.Errorf(codes.OutOfRange, "fromHeight %d vượt quá block cuối %d", req.FromHeight, to)
+	}
+	batchSize := int(req.BatchSize)
+	if batchSize <= 0 {
+		batchSize = defaultStreamBatch
+	}
+	if batchSize > maxStreamBatch {
+		batchSize = maxStreamBatch
+	}
+
+	batch := &pb.BlockBatch{TipHeight: tip.Header.Height}
+	size := 0
+	flush := func() error {
+		if len(batch.Blocks) == 0 {
+			return nil
+		}
+		if err := stream.Send(batch); err != nil {
+			return err
+		}
+		batch = &pb.BlockBatch{TipHeight: tip.Header.Height}
+		size = 0
+		return nil
+	}
+
+	for height := req.FromHeight; height <= to; height++ {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		block, err := s.Storage.LoadBlockByHeight(height)
+		if err == leveldb.ErrNotFound {
+			// Chuỗi chính vừa bị rút ngắn do đổi nhánh; client sẽ tìm lại block chung
+			break
+		}
+		if err != nil {
+			return status.Errorf(codes.Internal, "không tải được block %d: %v", height, err)
+		}
+
+		protoBlock := utils.ConvertToProtoBlock(block)
+		if req.HeadersOnly {
+			protoBlock.Transactions = nil
+		}
+		blockSize := proto.Size(protoBlock)
+		if len(batch.Blocks) >= batchSize || (len(batch.Blocks) > 0 && size+blockSize > maxStreamBatchSize) {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		batch.Blocks = append(batch.Blocks, protoBlock)
+		size += blockSize
+	}
+	if err := flush(); err != nil {
+		log.Printf("Gửi lô block thất bại: %v", err)
+		return err
+	}
+	return nil
+}
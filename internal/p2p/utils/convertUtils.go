@@ -1,50 +1,147 @@
 package utils
+
 import (
 	"github.com/chauduongphattien/golang-chain/internal/blockchain"
 
 	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
-
 )
 
 func ConvertFromProtoBlock(pbBlock *pb.Block) *blockchain.Block {
 	txs := make([]blockchain.Transaction, 0)
 	for _, pbTx := range pbBlock.Transactions {
-		txs = append(txs, blockchain.Transaction{
-			Sender:    pbTx.Sender,
-			Receiver:  pbTx.Receiver,
-			Amount:    pbTx.Amount,
-			Timestamp: pbTx.Timestamp,
-			Signature: pbTx.Signature,
-		})
+		txs = append(txs, ConvertFromProtoTransaction(pbTx))
 	}
 
 	return &blockchain.Block{
-		Timestamp:    pbBlock.Timestamp,
-		Transactions: txs, 
-		PrevHash:     pbBlock.PrevHash,
+		Header:       ConvertFromProtoHeader(pbBlock.Header),
+		Transactions: txs,
 		Hash:         pbBlock.Hash,
-		MerkleRoot:   pbBlock.MerkleRoot,
+		Certificate:  ConvertFromProtoCertificate(pbBlock.Certificate),
+	}
+}
+
+func ConvertFromProtoTransaction(pbTx *pb.Transaction) blockchain.Transaction {
+	return blockchain.Transaction{
+		Sender:    pbTx.GetSender(),
+		Receiver:  pbTx.GetReceiver(),
+		Amount:    blockchain.Amount(pbTx.GetAmount()),
+		Fee:       blockchain.Amount(pbTx.GetFee()),
+		Timestamp: pbTx.GetTimestamp(),
+		Signature: pbTx.GetSignature(),
+		PublicKey: pbTx.GetPublicKey(),
+		Nonce:     pbTx.GetNonce(),
+	}
+}
+
+func ConvertToProtoTransaction(t *blockchain.Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		Sender:    t.Sender,
+		Receiver:  t.Receiver,
+		Amount:    uint64(t.Amount),
+		Fee:       uint64(t.Fee),
+		Timestamp: t.Timestamp,
+		Signature: t.Signature,
+		PublicKey: t.PublicKey,
+		Nonce:     t.Nonce,
+	}
+}
+
+func ConvertFromProtoHeader(h *pb.BlockHeader) blockchain.BlockHeader {
+	return blockchain.BlockHeader{
+		Height:         h.GetHeight(),
+		Version:        h.GetVersion(),
+		ChainID:        h.GetChainID(),
+		PrevHash:       h.GetPrevHash(),
+		MerkleRoot:     h.GetMerkleRoot(),
+		TxCount:        h.GetTxCount(),
+		StateRoot:      h.GetStateRoot(),
+		Timestamp:      h.GetTimestamp(),
+		Proposer:       h.GetProposer(),
+		Nonce:          h.GetNonce(),
+		Difficulty:     h.GetDifficulty(),
+		ValidatorsHash: h.GetValidatorsHash(),
 	}
 }
 
 func ConvertToProtoBlock(b *blockchain.Block) *pb.Block {
 	var txs []*pb.Transaction
-	for _, t := range b.Transactions {
-		txs = append(txs, &pb.Transaction{
-			Sender:    t.Sender,
-			Receiver:  t.Receiver,
-			Amount:    t.Amount,
-			Timestamp: t.Timestamp,
-			Signature: t.Signature,
-		})
+	for i := range b.Transactions {
+		txs = append(txs, ConvertToProtoTransaction(&b.Transactions[i]))
 	}
 
 	return &pb.Block{
-		Timestamp:    b.Timestamp,
+		Header:       ConvertToProtoHeader(&b.Header),
 		Transactions: txs,
-		MerkleRoot:   b.MerkleRoot,
-		PrevHash:     b.PrevHash,
-		Nonce:        int32(b.Nonce),
 		Hash:         b.Hash,
+		Certificate:  ConvertToProtoCertificate(b.Certificate),
+	}
+}
+
+func ConvertToProtoHeader(h *blockchain.BlockHeader) *pb.BlockHeader {
+	return &pb.BlockHeader{
+		Height:         h.Height,
+		Version:        h.Version,
+		ChainID:        h.ChainID,
+		PrevHash:       h.PrevHash,
+		MerkleRoot:     h.MerkleRoot,
+		TxCount:        h.TxCount,
+		StateRoot:      h.StateRoot,
+		Timestamp:      h.Timestamp,
+		Proposer:       h.Proposer,
+		Nonce:          h.Nonce,
+		Difficulty:     h.Difficulty,
+		ValidatorsHash: h.ValidatorsHash,
+	}
+}
+
+func ConvertFromProtoVote(v *pb.Vote) blockchain.Vote {
+	return blockchain.Vote{
+		Height:      v.GetHeight(),
+		Round:       v.GetRound(),
+		BlockHash:   v.GetBlockHash(),
+		ValidatorID: v.GetValidatorID(),
+		Signature:   v.GetSignature(),
+	}
+}
+
+func ConvertToProtoVote(v *blockchain.Vote) *pb.Vote {
+	return &pb.Vote{
+		Height:      v.Height,
+		Round:       v.Round,
+		BlockHash:   v.BlockHash,
+		ValidatorID: v.ValidatorID,
+		Signature:   v.Signature,
+	}
+}
+
+func ConvertFromProtoCertificate(c *pb.CommitCertificate) *blockchain.CommitCertificate {
+	if c == nil {
+		return nil
 	}
-}
\ No newline at end of file
+	votes := make([]blockchain.Vote, 0, len(c.Votes))
+	for _, v := range c.Votes {
+		votes = append(votes, ConvertFromProtoVote(v))
+	}
+	return &blockchain.CommitCertificate{
+		Height:    c.Height,
+		Round:     c.Round,
+		BlockHash: c.BlockHash,
+		Votes:     votes,
+	}
+}
+
+func ConvertToProtoCertificate(c *blockchain.CommitCertificate) *pb.CommitCertificate {
+	if c == nil {
+		return nil
+	}
+	votes := make([]*pb.Vote, 0, len(c.Votes))
+	for i := range c.Votes {
+		votes = append(votes, ConvertToProtoVote(&c.Votes[i]))
+	}
+	return &pb.CommitCertificate{
+		Height:    c.Height,
+		Round:     c.Round,
+		BlockHash: c.BlockHash,
+		Votes:     votes,
+	}
+}
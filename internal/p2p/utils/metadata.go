@@ -0,0 +1,5 @@
+package utils
+
+// GenesisHashMetadataKey là khoá metadata gRPC mà mọi node gửi kèm để
+// hai bên xác nhận cùng một chuỗi (cùng genesis block).
+const GenesisHashMetadataKey = "genesis-hash"
@@ -0,0 +1,170 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/pkg/storage"
+)
+
+// Peers là nguồn peer để tải block (peers.Manager thoả mãn). Misbehaved được gọi khi peer gửi dữ liệu sai.
+type Peers interface {
+	Addresses() []string
+	Misbehaved(address, reason string)
+}
+
+// misbehaving cho biết lỗi đồng bộ là do dữ liệu peer gửi sai chứ không phải do mạng hay node này.
+// Block của leader cũ (NOT_LEADER) có thể hợp lệ lúc được tạo nên không bị tính.
+func misbehaving(err error) bool {
+	if errors.Is(err, ErrNoCommonAncestor) || errors.Is(err, ErrBadHeaderChain) || errors.Is(err, ErrBlockMismatch) {
+		return true
+	}
+	switch blockchain.RejectReasonOf(err) {
+	case blockchain.ReasonInternal, blockchain.ReasonNotLeader:
+		return false
+	default:
+		return true
+	}
+}
+
+// PeerStatus là block cuối của một peer ở lần hỏi gần nhất.
+type PeerStatus struct {
+	Address   string    `json:"address"`
+	TipHeight uint64    `json:"tip_height"`
+	TipHash   string    `json:"tip_hash,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Status là trạng thái đồng bộ, trả về qua GET /sync/status.
+type Status struct {
+	Running       bool                  `json:"running"`
+	Syncing       bool                  `json:"syncing"`
+	CurrentHeight uint64                `json:"current_height"`
+	CurrentHash   string                `json:"current_hash"`
+	TargetHeight  uint64                `json:"target_height"`
+	Peers         []PeerStatus          `json:"peers"`
+	Progress      *storage.SyncProgress `json:"progress,omitempty"`
+	LastError     string                `json:"last_error,omitempty"`
+}
+
+// Trigger yêu cầu vòng đồng bộ nền chạy ngay (ví dụ khi nhận block chưa có block cha).
+func (s *Syncer) Trigger() {
+	select {
+	case s.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run định kỳ hỏi block cuối của các peer và tải block từ peer có chuỗi cao nhất mà node chưa có,
+// cho tới khi ctx bị huỷ.
+func (s *Syncer) Run(ctx context.Context, peers Peers) {
+	if s.cfg.Interval <= 0 {
+		return
+	}
+	s.statusMu.Lock()
+	s.status.Running = true
+	s.statusMu.Unlock()
+	log.Printf("Đồng bộ nền mỗi %s", s.cfg.Interval)
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		s.syncOnce(ctx, peers)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.trigger:
+		}
+	}
+}
+
+// syncOnce hỏi block cuối của từng peer và đồng bộ từ peer cao nhất có block cuối mà node chưa có.
+func (s *Syncer) syncOnce(ctx context.Context, source Peers) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		bestAddr string
+		bestResp *pb.LocatorResponse
+	)
+	addrs := source.Addresses()
+	peers := make([]PeerStatus, 0, len(addrs))
+	for _, addr := range addrs {
+		peer := PeerStatus{Address: addr, CheckedAt: time.Now()}
+		resp, err := s.findCommonAncestor(addr)
+		if err != nil {
+			if misbehaving(err) {
+				source.Misbehaved(addr, err.Error())
+			}
+			peer.Error = err.Error()
+			peers = append(peers, peer)
+			continue
+		}
+		peer.TipHeight, peer.TipHash = resp.TipHeight, resp.TipHash
+		peers = append(peers, peer)
+
+		if _, err := s.store.LoadBlockMeta(resp.TipHash); err == nil {
+			continue
+		}
+		if bestResp == nil || resp.TipHeight > bestResp.TipHeight {
+			bestAddr, bestResp = addr, resp
+		}
+	}
+
+	s.statusMu.Lock()
+	s.status.Peers = peers
+	s.status.Syncing = bestResp != nil
+	s.statusMu.Unlock()
+	if bestResp == nil {
+		return
+	}
+
+	_, err := s.syncFrom(ctx, bestAddr, bestResp)
+
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	s.status.Syncing = false
+	if err != nil {
+		log.Printf("Đồng bộ nền từ %s thất bại: %v", bestAddr, err)
+		if misbehaving(err) {
+			source.Misbehaved(bestAddr, err.Error())
+		}
+		s.status.LastError = err.Error()
+	} else {
+		s.status.LastError = ""
+	}
+}
+
+func (s *Syncer) setProgress(progress *storage.SyncProgress) {
+	copied := *progress
+	s.statusMu.Lock()
+	s.status.Progress = &copied
+	s.statusMu.Unlock()
+}
+
+// Status trả về trạng thái đồng bộ; TargetHeight là height cao nhất mà node biết (của chính nó hoặc của peer).
+func (s *Syncer) Status() Status {
+	s.statusMu.Lock()
+	status := s.status
+	status.Peers = append([]PeerStatus(nil), s.status.Peers...)
+	s.statusMu.Unlock()
+
+	if tip, err := s.store.GetLatestBlock(); err == nil {
+		status.CurrentHeight = tip.Header.Height
+		status.CurrentHash = tip.Hash
+	}
+	status.TargetHeight = status.CurrentHeight
+	for _, peer := range status.Peers {
+		if peer.Error == "" && peer.TipHeight > status.TargetHeight {
+			status.TargetHeight = peer.TipHeight
+		}
+	}
+	return status
+}
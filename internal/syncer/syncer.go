@@ -0,0 +1,284 @@
+// Package syncer tải các block còn thiếu từ peer: tìm block chung bằng locator, tải và kiểm tra
+// header theo từng cửa sổ, rồi mới tải nội dung block theo lô qua StreamBlocks.
+package syncer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+	"github.com/chauduongphattien/golang-chain/pkg/storage"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	ErrNoCommonAncestor = errors.New("peer không có block chung với node này (khác genesis?)")
+	ErrBadHeaderChain   = errors.New("chuỗi header từ peer không hợp lệ")
+	ErrBlockMismatch    = errors.New("block không khớp header đã tải")
+)
+
+type Config struct {
+	BatchSize    uint32        // số block mỗi lô khi tải nội dung
+	HeaderWindow uint64        // số header tải và kiểm tra trước mỗi lượt tải nội dung
+	Interval     time.Duration // chu kỳ hỏi block cuối của các peer khi chạy nền, 0 là tắt
+}
+
+func DefaultConfig() Config {
+	return Config{BatchSize: 64, HeaderWindow: 2000, Interval: 3 * time.Second}
+}
+
+// LoadConfig đọc SYNC_BATCH_SIZE, SYNC_HEADER_WINDOW và SYNC_INTERVAL.
+func LoadConfig() Config {
+	cfg := DefaultConfig()
+	if raw := os.Getenv("SYNC_INTERVAL"); raw != "" {
+		if d, err := time.ParseDuration(raw); err == nil && d >= 0 {
+			cfg.Interval = d
+		} else {
+			log.Printf("SYNC_INTERVAL không hợp lệ (%q), dùng %s", raw, cfg.Interval)
+		}
+	}
+	if raw := os.Getenv("SYNC_BATCH_SIZE"); raw != "" {
+		if n, err := strconv.ParseUint(raw, 10, 32); err == nil && n > 0 {
+			cfg.BatchSize = uint32(n)
+		} else {
+			log.Printf("SYNC_BATCH_SIZE không hợp lệ (%q), dùng %d", raw, cfg.BatchSize)
+		}
+	}
+	if raw := os.Getenv("SYNC_HEADER_WINDOW"); raw != "" {
+		if n, err := strconv.ParseUint(raw, 10, 64); err == nil && n > 0 {
+			cfg.HeaderWindow = n
+		} else {
+			log.Printf("SYNC_HEADER_WINDOW không hợp lệ (%q), dùng %d", raw, cfg.HeaderWindow)
+		}
+	}
+	return cfg
+}
+
+type Syncer struct {
+	store     *storage.Storage
+	engine    consensus.Engine
+	validator *blockchain.Validator
+	cfg       Config
+	// mu đảm bảo mỗi lúc chỉ có một lượt đồng bộ
+	mu      sync.Mutex
+	trigger chan struct{}
+
+	statusMu sync.Mutex
+	status   Status
+}
+
+func New(store *storage.Storage, engine consensus.Engine, cfg Config) *Syncer {
+	return &Syncer{
+		store:     store,
+		engine:    engine,
+		validator: blockchain.NewValidator(store, store, network.VerifyTransactionSignature),
+		cfg:       cfg,
+		trigger:   make(chan struct{}, 1),
+	}
+}
+
+// Sync tải từ peer tại address mọi block sau block chung cho tới block cuối của peer.
+// Tiến độ được lưu sau mỗi lô; nếu lượt trước với cùng peer bị ngắt, lượt này tiếp tục từ block đã lưu cuối.
+func (s *Syncer) Sync(ctx context.Context, address string) (*storage.SyncProgress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp, err := s.findCommonAncestor(address)
+	if err != nil {
+		return nil, err
+	}
+	return s.syncFrom(ctx, address, resp)
+}
+
+func (s *Syncer) findCommonAncestor(address string) (*pb.LocatorResponse, error) {
+	// Chuỗi luôn có genesis (InitGenesis chạy lúc khởi động) nên locator không bao giờ rỗng;
+	// node mới chỉ có genesis sẽ tải từ block 1.
+	locator, err := s.store.BlockLocator()
+	if err != nil {
+		return nil, fmt.Errorf("không tạo được locator: %w", err)
+	}
+	resp, err := grpcclient.FindCommonAncestor(address, locator)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found {
+		return nil, ErrNoCommonAncestor
+	}
+	return resp, nil
+}
+
+func (s *Syncer) syncFrom(ctx context.Context, address string, resp *pb.LocatorResponse) (*storage.SyncProgress, error) {
+	progress := &storage.SyncProgress{
+		Peer:           address,
+		TargetHeight:   resp.TipHeight,
+		TargetHash:     resp.TipHash,
+		AncestorHeight: resp.Height,
+		HeaderHeight:   resp.Height,
+		LastHeight:     resp.Height,
+		LastHash:       resp.Hash,
+		UpdatedAt:      time.Now(),
+	}
+	s.resume(progress)
+
+	if _, err := s.store.LoadBlockMeta(resp.TipHash); err == nil {
+		progress.Done = true
+		s.setProgress(progress)
+		return progress, s.store.SaveSyncProgress(progress)
+	}
+	log.Printf("Đồng bộ từ %s: block chung %d, cần tải tới %d", address, progress.LastHeight, progress.TargetHeight)
+
+	for progress.LastHeight < progress.TargetHeight {
+		from := progress.LastHeight + 1
+		to := progress.LastHeight + s.cfg.HeaderWindow
+		if to > progress.TargetHeight {
+			to = progress.TargetHeight
+		}
+
+		headers, err := s.fetchHeaders(ctx, address, from, to, progress.LastHash)
+		if err != nil {
+			return s.fail(progress, err)
+		}
+		if len(headers) == 0 {
+			// Chuỗi của peer ngắn lại (đổi nhánh); lượt sau sẽ tìm lại block chung
+			break
+		}
+		progress.HeaderHeight = headers[len(headers)-1].Header.Height
+
+		s.setProgress(progress)
+		if err := s.fetchBlocks(ctx, address, headers, progress); err != nil {
+			return s.fail(progress, err)
+		}
+		if uint64(len(headers)) < to-from+1 {
+			break
+		}
+	}
+
+	progress.Done = true
+	progress.UpdatedAt = time.Now()
+	s.setProgress(progress)
+	log.Printf("Đồng bộ từ %s xong tại block %d (%s)", address, progress.LastHeight, progress.LastHash)
+	return progress, s.store.SaveSyncProgress(progress)
+}
+
+// resume tiếp tục từ lượt đồng bộ trước nếu lượt đó chưa xong, cùng peer, và block đã lưu cuối
+// của nó vẫn còn trong kho (có thể thuộc nhánh chưa trở thành chuỗi chính).
+func (s *Syncer) resume(progress *storage.SyncProgress) {
+	prev, err := s.store.LoadSyncProgress()
+	if err != nil || prev == nil || prev.Done || prev.Error != "" || prev.Peer != progress.Peer {
+		return
+	}
+	if prev.LastHeight <= progress.LastHeight || prev.LastHeight > progress.TargetHeight {
+		return
+	}
+	if _, err := s.store.LoadBlockMeta(prev.LastHash); err != nil {
+		return
+	}
+	progress.AncestorHeight = prev.AncestorHeight
+	progress.HeaderHeight = prev.LastHeight
+	progress.LastHeight = prev.LastHeight
+	progress.LastHash = prev.LastHash
+	log.Printf("Tiếp tục lượt đồng bộ trước từ block %d", prev.LastHeight)
+}
+
+func (s *Syncer) fail(progress *storage.SyncProgress, err error) (*storage.SyncProgress, error) {
+	progress.Error = err.Error()
+	progress.UpdatedAt = time.Now()
+	s.setProgress(progress)
+	if saveErr := s.store.SaveSyncProgress(progress); saveErr != nil {
+		log.Printf("Không lưu được tiến độ đồng bộ: %v", saveErr)
+	}
+	return progress, err
+}
+
+// fetchHeaders tải header [from, to] và kiểm tra hash, liên kết với block trước và phần đồng thuận
+// của từng header, trước khi tải nội dung.
+func (s *Syncer) fetchHeaders(ctx context.Context, address string, from, to uint64, prevHash string) ([]*blockchain.Block, error) {
+	var headers []*blockchain.Block
+	req := &pb.StreamBlocksRequest{FromHeight: from, ToHeight: to, HeadersOnly: true}
+	err := grpcclient.StreamBlocks(ctx, address, req, func(batch []*blockchain.Block) error {
+		for _, header := range batch {
+			height := from + uint64(len(headers))
+			switch {
+			case header.Header.Height != height:
+				return fmt.Errorf("%w: mong đợi height %d, nhận %d", ErrBadHeaderChain, height, header.Header.Height)
+			case header.Header.PrevHash != prevHash:
+				return fmt.Errorf("%w: block %d không nối tiếp %s", ErrBadHeaderChain, height, prevHash)
+			case header.CalculateHash() != header.Hash:
+				return fmt.Errorf("%w: hash block %d không khớp header", ErrBadHeaderChain, height)
+			}
+			if err := s.engine.VerifyHeader(header); err != nil {
+				return fmt.Errorf("%w: block %d: %v", ErrBadHeaderChain, height, err)
+			}
+			headers = append(headers, header)
+			prevHash = header.Hash
+		}
+		return nil
+	})
+	return headers, err
+}
+
+// fetchBlocks tải nội dung các block đã có header, kiểm tra đầy đủ rồi lưu theo từng lô.
+func (s *Syncer) fetchBlocks(ctx context.Context, address string, headers []*blockchain.Block, progress *storage.SyncProgress) error {
+	from := headers[0].Header.Height
+	to := headers[len(headers)-1].Header.Height
+	req := &pb.StreamBlocksRequest{FromHeight: from, ToHeight: to, BatchSize: s.cfg.BatchSize}
+	return grpcclient.StreamBlocks(ctx, address, req, func(batch []*blockchain.Block) error {
+		for _, block := range batch {
+			i := block.Header.Height - from
+			if block.Header.Height < from || i >= uint64(len(headers)) || block.Hash != headers[i].Hash {
+				return fmt.Errorf("%w: block %d (%s)", ErrBlockMismatch, block.Header.Height, block.Hash)
+			}
+			if err := s.apply(block); err != nil {
+				return fmt.Errorf("block %d (%s) không hợp lệ: %w", block.Header.Height, block.Hash, err)
+			}
+			progress.LastHeight = block.Header.Height
+			progress.LastHash = block.Hash
+		}
+		progress.UpdatedAt = time.Now()
+		s.setProgress(progress)
+		return s.store.SaveSyncProgress(progress)
+	})
+}
+
+// apply kiểm tra block với block cha rồi lưu qua fork choice. Block nối tiếp block cuối được kiểm tra
+// đầy đủ; block ở nhánh khác được kiểm tra nonce, số dư và state root khi storage chuyển sang nhánh đó.
+func (s *Syncer) apply(block *blockchain.Block) error {
+	parent, err := s.store.LoadBlock(block.Header.PrevHash)
+	if err == leveldb.ErrNotFound {
+		return storage.ErrUnknownParent
+	}
+	if err != nil {
+		return err
+	}
+	tip, err := s.store.GetLatestBlock()
+	if err != nil {
+		return err
+	}
+
+	if parent.Hash == tip.Hash {
+		err = s.validator.ValidateBlock(block, parent)
+	} else {
+		err = s.validator.ValidateBlockStructure(block, parent)
+	}
+	if err == nil {
+		err = s.engine.Validate(block, parent)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.store.AddBlock(block); err != nil && !errors.Is(err, storage.ErrKnownBlock) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,359 @@
+// Package lightclient kiểm tra chuỗi mà không chạy full node: chỉ tải header (kèm commit certificate)
+// qua StreamBlocks, tự kiểm tra hash, liên kết, certificate hoặc proof-of-work bắt đầu từ genesis,
+// rồi dùng các header đã kiểm tra để xác minh Merkle proof và state proof do một full node bất kỳ
+// (không tin cậy) gửi về.
+package lightclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/consensus/pow"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/grpcclient"
+)
+
+var (
+	ErrNoCommonAncestor = errors.New("full node không có block chung với light client (khác genesis?)")
+	ErrBadHeader        = errors.New("header từ full node không hợp lệ")
+	ErrUnknownBlock     = errors.New("block không nằm trên chuỗi header đã kiểm tra (cần Sync)")
+	ErrProofMismatch    = errors.New("proof không khớp giao dịch hoặc tài khoản được hỏi")
+)
+
+// locatorDenseHeaders là số header gần nhất được đưa hết vào locator trước khi giãn khoảng cách.
+const locatorDenseHeaders = 10
+
+type Config struct {
+	// Consensus là thuật toán đồng thuận của chuỗi ("vote" hoặc "pow"), phải giống các full node
+	Consensus string
+	// Pow là cấu hình độ khó khi Consensus là "pow", phải giống các full node (Threads không dùng);
+	// DefaultConfig đọc từ các biến POW_* như full node
+	Pow pow.Config
+	// HeaderWindow là số header tải trong mỗi lần gọi StreamBlocks
+	HeaderWindow uint64
+}
+
+func DefaultConfig() Config {
+	return Config{Consensus: "vote", Pow: pow.LoadConfig(), HeaderWindow: 2000}
+}
+
+type header struct {
+	block *blockchain.Block // chỉ có header, hash và certificate
+	work  *big.Int          // tổng work từ genesis tới block
+}
+
+// Client an toàn khi dùng đồng thời. Header được giữ trong bộ nhớ.
+type Client struct {
+	cfg        Config
+	genesis    *blockchain.Block
+	validators consensus.ValidatorSets
+
+	mu        sync.RWMutex
+	byHash    map[string]*header
+	canonical []string // hash header trên chuỗi tốt nhất, theo height
+}
+
+// New tạo light client tin cậy genesis (cùng file genesis.json với các node). Genesis hash cũng được
+// đặt cho mọi lời gọi gRPC của grpcclient.
+func New(genesis *blockchain.Genesis, cfg Config) (*Client, error) {
+	if cfg.Consensus != "vote" && cfg.Consensus != "pow" {
+		return nil, fmt.Errorf("%w: %q", consensus.ErrUnknownEngine, cfg.Consensus)
+	}
+	validators, err := consensus.NewValidatorSetsFromGenesis(genesis)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.HeaderWindow == 0 {
+		cfg.HeaderWindow = DefaultConfig().HeaderWindow
+	}
+
+	block := genesis.Block()
+	grpcclient.SetGenesisHash(block.Hash)
+	return &Client{
+		cfg:        cfg,
+		genesis:    block,
+		validators: validators,
+		byHash:     map[string]*header{block.Hash: {block: headerOnly(block), work: block.Work()}},
+		canonical:  []string{block.Hash},
+	}, nil
+}
+
+func headerOnly(block *blockchain.Block) *blockchain.Block {
+	return &blockchain.Block{Header: block.Header, Hash: block.Hash, Certificate: block.Certificate}
+}
+
+// LoadBlock trả về header đã kiểm tra theo hash (kể cả ở nhánh phụ); dùng để tính độ khó PoW.
+func (c *Client) LoadBlock(hash string) (*blockchain.Block, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	h, ok := c.byHash[hash]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBlock, hash)
+	}
+	return h.block, nil
+}
+
+// Tip trả về header cuối của chuỗi tốt nhất đã kiểm tra.
+func (c *Client) Tip() *blockchain.Block {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.byHash[c.canonical[len(c.canonical)-1]].block
+}
+
+// HeaderByHeight trả về header trên chuỗi tốt nhất tại height.
+func (c *Client) HeaderByHeight(height uint64) (*blockchain.Block, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if height >= uint64(len(c.canonical)) {
+		return nil, false
+	}
+	return c.byHash[c.canonical[height]].block, true
+}
+
+// canonicalHeader trả về header nếu hash nằm trên chuỗi tốt nhất.
+func (c *Client) canonicalHeader(hash string) (*blockchain.Block, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	h, ok := c.byHash[hash]
+	if !ok {
+		return nil, false
+	}
+	height := h.block.Header.Height
+	if height >= uint64(len(c.canonical)) || c.canonical[height] != hash {
+		return nil, false
+	}
+	return h.block, true
+}
+
+// locator trả về hash các header trên chuỗi tốt nhất từ header cuối lùi về, thưa dần, kết thúc bằng genesis
+// (cùng cách storage.BlockLocator).
+func (c *Client) locator() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var hashes []string
+	height := uint64(len(c.canonical) - 1)
+	step := uint64(1)
+	for {
+		hashes = append(hashes, c.canonical[height])
+		if height == 0 {
+			return hashes
+		}
+		if len(hashes) >= locatorDenseHeaders {
+			step *= 2
+		}
+		if height < step {
+			height = 0
+		} else {
+			height -= step
+		}
+	}
+}
+
+// Sync tải và kiểm tra header từ full node tại address cho tới header cuối của node đó. Nhánh của full node
+// chỉ được dùng nếu có nhiều work hơn chuỗi đang có (với đồng thuận bỏ phiếu: dài hơn). Trả về header cuối.
+func (c *Client) Sync(ctx context.Context, address string) (*blockchain.Block, error) {
+	resp, err := grpcclient.FindCommonAncestor(address, c.locator())
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Found {
+		return nil, ErrNoCommonAncestor
+	}
+	if _, err := c.LoadBlock(resp.Hash); err != nil {
+		return nil, fmt.Errorf("%w: block chung %s", ErrBadHeader, resp.Hash)
+	}
+
+	lastHash := resp.Hash
+	for from := resp.Height + 1; from <= resp.TipHeight; {
+		to := min(from+c.cfg.HeaderWindow-1, resp.TipHeight)
+		next, err := c.fetchHeaders(ctx, address, from, to, lastHash)
+		if err != nil {
+			return nil, err
+		}
+		if next == lastHash {
+			// Chuỗi của full node ngắn lại trong lúc tải
+			break
+		}
+		lastHash = next
+		from = to + 1
+	}
+
+	c.adopt(lastHash)
+	return c.Tip(), nil
+}
+
+// fetchHeaders tải header [from, to] nối tiếp prevHash, kiểm tra từng header rồi lưu vào bộ nhớ.
+// Trả về hash header cuối đã lưu.
+func (c *Client) fetchHeaders(ctx context.Context, address string, from, to uint64, prevHash string) (string, error) {
+	req := &pb.StreamBlocksRequest{FromHeight: from, ToHeight: to, HeadersOnly: true}
+	err := grpcclient.StreamBlocks(ctx, address, req, func(batch []*blockchain.Block) error {
+		for _, block := range batch {
+			parent, err := c.LoadBlock(prevHash)
+			if err != nil {
+				return err
+			}
+			if err := c.verifyHeader(block, parent); err != nil {
+				return err
+			}
+			c.store(block, prevHash)
+			prevHash = block.Hash
+		}
+		return nil
+	})
+	return prevHash, err
+}
+
+// verifyHeader kiểm tra header nối tiếp parent: height, liên kết, chain ID, hash, rồi certificate
+// (bỏ phiếu) hoặc độ khó và proof-of-work.
+func (c *Client) verifyHeader(block, parent *blockchain.Block) error {
+	switch {
+	case block.Header.Height != parent.Header.Height+1:
+		return fmt.Errorf("%w: mong đợi height %d, nhận %d", ErrBadHeader, parent.Header.Height+1, block.Header.Height)
+	case block.Header.PrevHash != parent.Hash:
+		return fmt.Errorf("%w: block %d không nối tiếp %s", ErrBadHeader, block.Header.Height, parent.Hash)
+	case block.Header.ChainID != c.genesis.Header.ChainID:
+		return fmt.Errorf("%w: block %d có chain ID %q", ErrBadHeader, block.Header.Height, block.Header.ChainID)
+	case block.CalculateHash() != block.Hash:
+		return fmt.Errorf("%w: hash block %d không khớp header", ErrBadHeader, block.Header.Height)
+	}
+
+	if c.cfg.Consensus == "pow" {
+		expected, err := pow.ExpectedDifficulty(c, c.cfg.Pow, parent)
+		if err != nil {
+			return err
+		}
+		if block.Header.Difficulty != expected || !pow.MeetsDifficulty(block.Hash, block.Header.Difficulty) {
+			return fmt.Errorf("%w: block %d không đạt độ khó %d", ErrBadHeader, block.Header.Height, expected)
+		}
+		return nil
+	}
+	set, err := c.validators.ValidatorSetAt(block.Header.Height)
+	if err != nil {
+		return err
+	}
+	if err := consensus.VerifyCertificate(set, block, block.Certificate); err != nil {
+		return fmt.Errorf("%w: block %d: %v", ErrBadHeader, block.Header.Height, err)
+	}
+	return nil
+}
+
+func (c *Client) store(block *blockchain.Block, prevHash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.byHash[block.Hash]; ok {
+		return
+	}
+	work := block.Work()
+	work.Add(work, c.byHash[prevHash].work)
+	c.byHash[block.Hash] = &header{block: headerOnly(block), work: work}
+}
+
+// adopt chuyển chuỗi tốt nhất sang nhánh kết thúc tại hash nếu nhánh đó có nhiều work hơn.
+func (c *Client) adopt(hash string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tip := c.byHash[c.canonical[len(c.canonical)-1]]
+	candidate := c.byHash[hash]
+	if candidate.work.Cmp(tip.work) <= 0 {
+		return
+	}
+
+	height := candidate.block.Header.Height
+	branch := make([]string, height+1)
+	for h := candidate; ; h = c.byHash[h.block.Header.PrevHash] {
+		i := h.block.Header.Height
+		if i < uint64(len(c.canonical)) && c.canonical[i] == h.block.Hash {
+			copy(branch, c.canonical[:i+1])
+			break
+		}
+		branch[i] = h.block.Hash
+	}
+	c.canonical = branch
+}
+
+// Inclusion là kết quả xác minh một giao dịch nằm trên chuỗi tốt nhất.
+type Inclusion struct {
+	TxHash        string `json:"tx_hash"`
+	BlockHash     string `json:"block_hash"`
+	Height        uint64 `json:"height"`
+	Index         int    `json:"index"`
+	Confirmations uint64 `json:"confirmations"`
+}
+
+// VerifyTransaction lấy Merkle proof của giao dịch từ full node tại address và kiểm tra proof với
+// MerkleRoot và TxCount của header đã được light client kiểm tra (header do full node gửi kèm bị bỏ qua).
+// Muốn xác minh một khoản thanh toán cụ thể, truyền tx.ID() của giao dịch mong đợi.
+func (c *Client) VerifyTransaction(address, txHash string) (*Inclusion, error) {
+	remote, proof, err := grpcclient.GetTransactionProof(address, txHash)
+	if err != nil {
+		return nil, err
+	}
+	if proof.TxHash != txHash {
+		return nil, fmt.Errorf("%w: nhận proof của %s", ErrProofMismatch, proof.TxHash)
+	}
+	block, ok := c.canonicalHeader(remote.Hash)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBlock, remote.Hash)
+	}
+	if err := block.Header.VerifyTxProof(proof); err != nil {
+		return nil, err
+	}
+
+	tip := c.Tip()
+	return &Inclusion{
+		TxHash:        txHash,
+		BlockHash:     block.Hash,
+		Height:        block.Header.Height,
+		Index:         proof.Index,
+		Confirmations: tip.Header.Height - block.Header.Height + 1,
+	}, nil
+}
+
+// Account là trạng thái tài khoản đã được xác minh tại một header trên chuỗi tốt nhất.
+type Account struct {
+	Address   string            `json:"address"`
+	Exists    bool              `json:"exists"`
+	Balance   blockchain.Amount `json:"balance"`
+	Nonce     uint64            `json:"nonce"`
+	BlockHash string            `json:"block_hash"`
+	Height    uint64            `json:"height"`
+}
+
+// VerifyAccount lấy state proof của tài khoản tại height từ full node tại address và kiểm tra proof với
+// StateRoot của header đã được light client kiểm tra tại height đó.
+func (c *Client) VerifyAccount(address, account string, height uint64) (*Account, error) {
+	block, ok := c.HeaderByHeight(height)
+	if !ok {
+		return nil, fmt.Errorf("%w: height %d", ErrUnknownBlock, height)
+	}
+	remote, proof, err := grpcclient.GetAccountProof(address, account, height)
+	if err != nil {
+		return nil, err
+	}
+	if remote.Hash != block.Hash {
+		return nil, fmt.Errorf("%w: full node trả proof tại block %s", ErrUnknownBlock, remote.Hash)
+	}
+	if proof.Address != account {
+		return nil, fmt.Errorf("%w: nhận proof của %s", ErrProofMismatch, proof.Address)
+	}
+	if err := blockchain.VerifyStateProof(proof, block.Header.StateRoot); err != nil {
+		return nil, err
+	}
+
+	result := &Account{Address: account, BlockHash: block.Hash, Height: height}
+	if proof.Account != nil {
+		result.Exists = true
+		result.Balance = proof.Account.Balance
+		result.Nonce = proof.Account.Nonce
+	}
+	return result, nil
+}
@@ -0,0 +1,460 @@
+package lightclient
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+
+	pb "github.com/chauduongphattien/golang-chain/blockchain/proposalpb"
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/service"
+	"github.com/chauduongphattien/golang-chain/internal/p2p/utils"
+	"github.com/chauduongphattien/golang-chain/pkg/storage"
+)
+
+// testChainHeight là số block sau genesis của chuỗi thử; tập validator đổi ở testUpdateHeight.
+// Mọi giao dịch của chuỗi thử chuyển tiền cho testReceiver.
+const (
+	testChainHeight  = 5
+	testUpdateHeight = 3
+	testReceiver     = "5ca1ab1e5ca1ab1e5ca1ab1e5ca1ab1e5ca1ab1e5ca1ab1e5ca1ab1e5ca1ab1e"
+)
+
+type testChain struct {
+	genesis *blockchain.Genesis
+	db      *storage.Storage
+	signers map[string]*consensus.Signer
+	sender  string
+	txs     []blockchain.Transaction // giao dịch theo thứ tự đã commit
+}
+
+func newSigner(t *testing.T, id string) (*consensus.Signer, string) {
+	t.Helper()
+	key, err := network.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := consensus.NewSigner(id, hex.EncodeToString(network.MarshalPrivateKey(key)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer, signer.PublicKey()
+}
+
+// newTestChain tạo full node trong bộ nhớ tạm: v1, v2, v3 bỏ phiếu cho các block trước testUpdateHeight,
+// sau đó là v1 và v4 (quorum 1/2). Mỗi block có hai giao dịch của cùng một người gửi.
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+
+	senderKey, err := network.GenerateKeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	senderPub := network.MarshalPublicKey(&senderKey.PublicKey)
+	c := &testChain{
+		signers: make(map[string]*consensus.Signer),
+		sender:  network.GetAddressFromPubKey(senderPub),
+	}
+
+	validator := func(id string) blockchain.GenesisValidator {
+		signer, pubKey := newSigner(t, id)
+		c.signers[id] = signer
+		return blockchain.GenesisValidator{ID: id, Address: id + ":50050", PublicKey: pubKey, Power: 1}
+	}
+	v1, v2, v3, v4 := validator("v1"), validator("v2"), validator("v3"), validator("v4")
+	c.genesis = &blockchain.Genesis{
+		ChainID:     "lightclient-test",
+		Timestamp:   1700000000,
+		Allocations: []blockchain.GenesisAllocation{{Address: c.sender, Balance: 1000}},
+		Validators:  []blockchain.GenesisValidator{v1, v2, v3},
+		ValidatorUpdates: []blockchain.GenesisValidatorUpdate{
+			{Height: testUpdateHeight, Validators: []blockchain.GenesisValidator{v1, v4}, Quorum: "1/2"},
+		},
+	}
+	if err := c.genesis.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	c.db = storage.NewStorage(t.TempDir())
+	t.Cleanup(c.db.Close)
+	if err := c.db.InitGenesis(c.genesis.Block()); err != nil {
+		t.Fatal(err)
+	}
+	sets, err := consensus.NewValidatorSetsFromGenesis(c.genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.db.InitValidatorSets(sets); err != nil {
+		t.Fatal(err)
+	}
+
+	var nonce uint64
+	for height := uint64(1); height <= testChainHeight; height++ {
+		parent, err := c.db.GetLatestBlock()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var txs []blockchain.Transaction
+		for i := 0; i < 2; i++ {
+			tx := blockchain.Transaction{
+				Sender:    c.sender,
+				Receiver:  testReceiver,
+				Amount:    10,
+				Timestamp: parent.Header.Timestamp + 1,
+				Nonce:     nonce,
+				PublicKey: senderPub,
+			}
+			if tx.Signature, err = network.GenerateSignature(&tx, senderKey); err != nil {
+				t.Fatal(err)
+			}
+			txs = append(txs, tx)
+			nonce++
+		}
+		root, err := c.db.NextStateRoot(parent, txs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		block := blockchain.NewBlock(parent, txs, root, parent.Header.Timestamp+1, "v1")
+
+		set, err := sets.ValidatorSetAt(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, v := range set.Validators {
+			ids = append(ids, v.ID)
+		}
+		block.Certificate = c.certificate(t, block, ids...)
+		if err := consensus.VerifyCertificate(set, block, block.Certificate); err != nil {
+			t.Fatalf("certificate block %d: %v", height, err)
+		}
+		if err := c.db.ApplyBlock(block); err != nil {
+			t.Fatal(err)
+		}
+		c.txs = append(c.txs, txs...)
+	}
+	return c
+}
+
+// certificate gom phiếu của các validator ids cho block (round 0).
+func (c *testChain) certificate(t *testing.T, block *blockchain.Block, ids ...string) *blockchain.CommitCertificate {
+	t.Helper()
+	cert := &blockchain.CommitCertificate{Height: block.Header.Height, BlockHash: block.Hash}
+	for _, id := range ids {
+		vote, err := c.signers[id].SignVote(block.Header.Height, 0, block.Hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cert.Votes = append(cert.Votes, vote)
+	}
+	return cert
+}
+
+// tamperingServer là full node không tin cậy: trả lời bằng ProposalServer thật rồi sửa phản hồi.
+type tamperingServer struct {
+	*service.ProposalServer
+	batch        func(*pb.BlockBatch)
+	txProof      func(*pb.TxProofResponse)
+	accountProof func(*pb.AccountProofResponse)
+}
+
+type tamperingStream struct {
+	pb.ProposalService_StreamBlocksServer
+	tamper func(*pb.BlockBatch)
+}
+
+func (s tamperingStream) Send(batch *pb.BlockBatch) error {
+	s.tamper(batch)
+	return s.ProposalService_StreamBlocksServer.Send(batch)
+}
+
+func (s *tamperingServer) StreamBlocks(req *pb.StreamBlocksRequest, stream pb.ProposalService_StreamBlocksServer) error {
+	if s.batch != nil {
+		stream = tamperingStream{ProposalService_StreamBlocksServer: stream, tamper: s.batch}
+	}
+	return s.ProposalServer.StreamBlocks(req, stream)
+}
+
+func (s *tamperingServer) GetTransactionProof(ctx context.Context, req *pb.TxProofRequest) (*pb.TxProofResponse, error) {
+	resp, err := s.ProposalServer.GetTransactionProof(ctx, req)
+	if err == nil && s.txProof != nil {
+		s.txProof(resp)
+	}
+	return resp, err
+}
+
+func (s *tamperingServer) GetAccountProof(ctx context.Context, req *pb.AccountProofRequest) (*pb.AccountProofResponse, error) {
+	resp, err := s.ProposalServer.GetAccountProof(ctx, req)
+	if err == nil && s.accountProof != nil {
+		s.accountProof(resp)
+	}
+	return resp, err
+}
+
+// serve chạy gRPC server của full node trên cổng ngẫu nhiên và trả về địa chỉ.
+func (c *testChain) serve(t *testing.T, srv *tamperingServer) string {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesisHash := c.genesis.Block().Hash
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(service.GenesisUnaryInterceptor(genesisHash)),
+		grpc.StreamInterceptor(service.GenesisStreamInterceptor(genesisHash)),
+	)
+	srv.ProposalServer = service.NewProposalServer(c.db, nil, nil, nil)
+	pb.RegisterProposalServiceServer(grpcServer, srv)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+	return lis.Addr().String()
+}
+
+func (c *testChain) newClient(t *testing.T) *Client {
+	t.Helper()
+	client, err := New(c.genesis, Config{Consensus: "vote", HeaderWindow: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func (c *testChain) syncedClient(t *testing.T, addr string) *Client {
+	t.Helper()
+	client := c.newClient(t)
+	if _, err := client.Sync(context.Background(), addr); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	return client
+}
+
+func TestSyncVerifiesHeadersAcrossValidatorSets(t *testing.T) {
+	chain := newTestChain(t)
+	addr := chain.serve(t, &tamperingServer{})
+
+	client := chain.newClient(t)
+	tip, err := client.Sync(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	want, err := chain.db.GetLatestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Header.Height != testChainHeight || tip.Hash != want.Hash {
+		t.Fatalf("tip = %d %s, mong đợi %d %s", tip.Header.Height, tip.Hash, testChainHeight, want.Hash)
+	}
+	for height := uint64(0); height <= testChainHeight; height++ {
+		header, ok := client.HeaderByHeight(height)
+		stored, err := chain.db.LoadBlockByHeight(height)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok || header.Hash != stored.Hash {
+			t.Errorf("header %d không khớp full node", height)
+		}
+		if len(header.Transactions) != 0 {
+			t.Errorf("header %d có giao dịch, light client chỉ giữ header", height)
+		}
+	}
+
+	// Gọi lại khi đã đồng bộ không đổi gì
+	if tip, err := client.Sync(context.Background(), addr); err != nil || tip.Hash != want.Hash {
+		t.Fatalf("Sync lần hai: %v, tip %v", err, tip)
+	}
+}
+
+func TestSyncRejectsBadCertificate(t *testing.T) {
+	chain := newTestChain(t)
+
+	tests := []struct {
+		name   string
+		height uint64
+		voters []string
+	}{
+		{"thiếu quorum", 1, []string{"v1", "v2"}},
+		{"validator không thuộc tập tại height", testUpdateHeight, []string{"v2", "v3"}},
+		{"validator mới trước khi có hiệu lực", testUpdateHeight - 1, []string{"v1", "v2", "v4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, err := chain.db.LoadBlockByHeight(tt.height)
+			if err != nil {
+				t.Fatal(err)
+			}
+			bad := utils.ConvertToProtoCertificate(chain.certificate(t, stored, tt.voters...))
+			addr := chain.serve(t, &tamperingServer{batch: func(batch *pb.BlockBatch) {
+				for _, block := range batch.Blocks {
+					if block.Header.Height == tt.height {
+						block.Certificate = bad
+					}
+				}
+			}})
+
+			client := chain.newClient(t)
+			if _, err := client.Sync(context.Background(), addr); !errors.Is(err, ErrBadHeader) {
+				t.Fatalf("Sync = %v, mong đợi ErrBadHeader", err)
+			}
+			if tip := client.Tip(); tip.Header.Height != 0 {
+				t.Errorf("light client nhận chuỗi tới height %d dù có certificate sai", tip.Header.Height)
+			}
+		})
+	}
+}
+
+func TestSyncRejectsTamperedHeader(t *testing.T) {
+	chain := newTestChain(t)
+	addr := chain.serve(t, &tamperingServer{batch: func(batch *pb.BlockBatch) {
+		for _, block := range batch.Blocks {
+			if block.Header.Height == 2 {
+				block.Header.Proposer = "v2"
+			}
+		}
+	}})
+
+	client := chain.newClient(t)
+	if _, err := client.Sync(context.Background(), addr); !errors.Is(err, ErrBadHeader) {
+		t.Fatalf("Sync = %v, mong đợi ErrBadHeader", err)
+	}
+}
+
+func TestVerifyTransaction(t *testing.T) {
+	chain := newTestChain(t)
+	addr := chain.serve(t, &tamperingServer{})
+	client := chain.syncedClient(t, addr)
+
+	for i, tx := range chain.txs {
+		inclusion, err := client.VerifyTransaction(addr, tx.ID())
+		if err != nil {
+			t.Fatalf("giao dịch #%d: %v", i, err)
+		}
+		height := uint64(i/2 + 1)
+		if inclusion.Height != height || inclusion.Index != i%2 {
+			t.Errorf("giao dịch #%d ở height %d vị trí %d, mong đợi %d vị trí %d", i, inclusion.Height, inclusion.Index, height, i%2)
+		}
+		if inclusion.Confirmations != testChainHeight-height+1 {
+			t.Errorf("giao dịch #%d có %d xác nhận", i, inclusion.Confirmations)
+		}
+	}
+}
+
+func TestVerifyTransactionRejectsTamperedProof(t *testing.T) {
+	chain := newTestChain(t)
+	client := chain.syncedClient(t, chain.serve(t, &tamperingServer{}))
+	txHash := chain.txs[0].ID()
+
+	tests := []struct {
+		name   string
+		tamper func(*pb.TxProofResponse)
+		want   error
+	}{
+		{"sai nút anh em", func(resp *pb.TxProofResponse) {
+			resp.Siblings[0] = chain.txs[2].ID()
+		}, nil},
+		{"sai vị trí", func(resp *pb.TxProofResponse) {
+			resp.Index = 1
+		}, nil},
+		{"proof của giao dịch khác", func(resp *pb.TxProofResponse) {
+			resp.TxHash = chain.txs[1].ID()
+		}, ErrProofMismatch},
+		{"block không có trên chuỗi đã kiểm tra", func(resp *pb.TxProofResponse) {
+			resp.BlockHash = chain.txs[1].ID()
+		}, ErrUnknownBlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := chain.serve(t, &tamperingServer{txProof: tt.tamper})
+			_, err := client.VerifyTransaction(addr, txHash)
+			if err == nil {
+				t.Fatal("proof bị sửa vẫn được chấp nhận")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("lỗi = %v, mong đợi %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyAccount(t *testing.T) {
+	chain := newTestChain(t)
+	addr := chain.serve(t, &tamperingServer{})
+	client := chain.syncedClient(t, addr)
+
+	for height := uint64(0); height <= testChainHeight; height++ {
+		acc, err := client.VerifyAccount(addr, chain.sender, height)
+		if err != nil {
+			t.Fatalf("height %d: %v", height, err)
+		}
+		spent := blockchain.Amount(20 * height)
+		if !acc.Exists || acc.Balance != 1000-spent || acc.Nonce != 2*height {
+			t.Errorf("height %d: %+v", height, acc)
+		}
+	}
+
+	acc, err := client.VerifyAccount(addr, testReceiver, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Exists {
+		t.Errorf("receiver chưa tồn tại ở genesis: %+v", acc)
+	}
+	acc, err = client.VerifyAccount(addr, testReceiver, testChainHeight)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !acc.Exists || acc.Balance != 20*testChainHeight {
+		t.Errorf("receiver: %+v", acc)
+	}
+
+	if _, err := client.VerifyAccount(addr, chain.sender, testChainHeight+1); !errors.Is(err, ErrUnknownBlock) {
+		t.Errorf("height chưa đồng bộ: %v", err)
+	}
+}
+
+func TestVerifyAccountRejectsTamperedProof(t *testing.T) {
+	chain := newTestChain(t)
+	client := chain.syncedClient(t, chain.serve(t, &tamperingServer{}))
+
+	tests := []struct {
+		name   string
+		tamper func(*pb.AccountProofResponse)
+		want   error
+	}{
+		{"sai số dư", func(resp *pb.AccountProofResponse) {
+			resp.Balance++
+		}, nil},
+		{"sai nonce", func(resp *pb.AccountProofResponse) {
+			resp.Nonce = 0
+		}, nil},
+		{"khai tài khoản không tồn tại", func(resp *pb.AccountProofResponse) {
+			resp.Exists = false
+		}, nil},
+		{"bỏ nút anh em", func(resp *pb.AccountProofResponse) {
+			resp.Siblings = resp.Siblings[1:]
+		}, nil},
+		{"proof của tài khoản khác", func(resp *pb.AccountProofResponse) {
+			resp.Address = testReceiver
+		}, ErrProofMismatch},
+		{"proof tại block khác", func(resp *pb.AccountProofResponse) {
+			resp.BlockHash = chain.genesis.Block().Hash
+		}, ErrUnknownBlock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := chain.serve(t, &tamperingServer{accountProof: tt.tamper})
+			_, err := client.VerifyAccount(addr, chain.sender, testChainHeight)
+			if err == nil {
+				t.Fatal("proof bị sửa vẫn được chấp nhận")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("lỗi = %v, mong đợi %v", err, tt.want)
+			}
+		})
+	}
+}
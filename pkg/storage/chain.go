@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	ErrUnknownParent = errors.New("chưa có block cha")
+	ErrKnownBlock    = errors.New("block đã có trong kho")
+	// ErrFinalizedReorg: nhánh mới rẽ ra trước block đã có commit certificate trên chuỗi chính
+	ErrFinalizedReorg = errors.New("không đổi nhánh qua block đã có commit certificate")
+)
+
+// BlockMeta là thông tin fork choice của một block đã lưu, tính trên nhánh kết thúc tại block đó.
+type BlockMeta struct {
+	Hash     string `json:"hash"`
+	PrevHash string `json:"prev_hash"`
+	Height   uint64 `json:"height"`
+	// TotalWork là tổng Work từ genesis tới block (số thập phân)
+	TotalWork string `json:"total_work"`
+	// FinalizedHeight là height của block có commit certificate cao nhất trên nhánh
+	FinalizedHeight uint64 `json:"finalized_height"`
+}
+
+func (m *BlockMeta) work() *big.Int {
+	work, ok := new(big.Int).SetString(m.TotalWork, 10)
+	if !ok {
+		return new(big.Int)
+	}
+	return work
+}
+
+// newBlockMeta tính meta của block từ meta của block cha (parent nil với genesis).
+func newBlockMeta(block *blockchain.Block, parent *BlockMeta) *BlockMeta {
+	work := block.Work()
+	meta := &BlockMeta{Hash: block.Hash, PrevHash: block.Header.PrevHash, Height: block.Header.Height}
+	if parent != nil {
+		work.Add(work, parent.work())
+		meta.FinalizedHeight = parent.FinalizedHeight
+	}
+	if block.Certificate != nil {
+		meta.FinalizedHeight = block.Header.Height
+	}
+	meta.TotalWork = work.String()
+	return meta
+}
+
+// betterThan là luật fork choice: nhánh có block được commit certificate cao hơn thắng,
+// sau đó tới nhánh có nhiều work hơn. Hai nhánh ngang nhau thì giữ nhánh hiện tại.
+func (m *BlockMeta) betterThan(other *BlockMeta) bool {
+	if m.FinalizedHeight != other.FinalizedHeight {
+		return m.FinalizedHeight > other.FinalizedHeight
+	}
+	return m.work().Cmp(other.work()) > 0
+}
+
+// ChainUpdate mô tả một lần block cuối thay đổi. Orphaned là các block bị bỏ khỏi chuỗi chính
+// (block cuối cũ đứng đầu), Applied là các block được thêm vào (theo thứ tự height tăng dần).
+type ChainUpdate struct {
+	Orphaned []*blockchain.Block
+	Applied  []*blockchain.Block
+}
+
+// OnChainUpdate đăng ký hàm được gọi mỗi khi block cuối thay đổi, sau khi dữ liệu đã được ghi.
+func (s *Storage) OnChainUpdate(fn func(*ChainUpdate)) {
+	s.listenersMu.Lock()
+	defer s.listenersMu.Unlock()
+	s.listeners = append(s.listeners, fn)
+}
+
+func (s *Storage) notify(update *ChainUpdate) {
+	s.listenersMu.Lock()
+	listeners := append([]func(*ChainUpdate){}, s.listeners...)
+	s.listenersMu.Unlock()
+	for _, fn := range listeners {
+		fn(update)
+	}
+}
+
+func metaKey(hash string) []byte {
+	return []byte("meta_" + hash)
+}
+
+func undoKey(hash string) []byte {
+	return []byte("undo_" + hash)
+}
+
+func (s *Storage) LoadBlockMeta(hash string) (*BlockMeta, error) {
+	data, err := s.db.Get(metaKey(hash), nil)
+	if err != nil {
+		return nil, err
+	}
+	var meta BlockMeta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+func putBlockMeta(batch *leveldb.Batch, meta *BlockMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	batch.Put(metaKey(meta.Hash), data)
+	return nil
+}
+
+// undoChanges ghi lại trạng thái trước block của mọi tài khoản bị block thay đổi,
+// để có thể khôi phục khi block bị bỏ khỏi chuỗi chính.
+func undoChanges(state blockchain.StateReader, changes blockchain.StateChanges) (blockchain.StateChanges, error) {
+	undo := blockchain.StateChanges{}
+	for address := range changes {
+		acc, err := state.GetAccountState(address)
+		if err != nil {
+			return nil, err
+		}
+		undo[address] = acc
+	}
+	return undo, nil
+}
+
+func putUndo(batch *leveldb.Batch, hash string, undo blockchain.StateChanges) error {
+	data, err := json.Marshal(undo)
+	if err != nil {
+		return err
+	}
+	batch.Put(undoKey(hash), data)
+	return nil
+}
+
+func (s *Storage) loadUndo(hash string) (blockchain.StateChanges, error) {
+	data, err := s.db.Get(undoKey(hash), nil)
+	if err != nil {
+		return nil, err
+	}
+	var undo blockchain.StateChanges
+	if err := json.Unmarshal(data, &undo); err != nil {
+		return nil, err
+	}
+	return undo, nil
+}
+
+// AddBlock lưu block thuộc bất kỳ nhánh nào có block cha đã biết rồi chạy fork choice.
+// Block nối tiếp block cuối được áp dụng ngay; block ở nhánh khác chỉ được lưu, trừ khi nhánh đó
+// tốt hơn chuỗi chính — khi đó trạng thái của các block bị bỏ được hoàn tác và nhánh mới được áp dụng
+// trong cùng một batch. Trả về nil nếu block cuối không đổi.
+//
+// Block phải được kiểm tra (hash, chữ ký, proof-of-work hoặc certificate) trước khi gọi;
+// nonce, số dư và state root được kiểm tra ở đây khi block được áp dụng.
+func (s *Storage) AddBlock(block *blockchain.Block) (*ChainUpdate, error) {
+	s.mu.Lock()
+	update, err := s.addBlock(block)
+	s.mu.Unlock()
+
+	if err != nil || update == nil {
+		return nil, err
+	}
+	s.notify(update)
+	return update, nil
+}
+
+func (s *Storage) addBlock(block *blockchain.Block) (*ChainUpdate, error) {
+	if _, err := s.db.Get(metaKey(block.Hash), nil); err == nil {
+		return nil, ErrKnownBlock
+	}
+	parentMeta, err := s.LoadBlockMeta(block.Header.PrevHash)
+	if err == leveldb.ErrNotFound {
+		return nil, fmt.Errorf("%w %s của block %s", ErrUnknownParent, block.Header.PrevHash, block.Hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if block.Header.Height != parentMeta.Height+1 {
+		return nil, fmt.Errorf("height không hợp lệ: mong đợi %d, nhận %d", parentMeta.Height+1, block.Header.Height)
+	}
+
+	tip, err := s.GetLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+	if block.Header.PrevHash == tip.Hash {
+		if err := s.applyBlock(block, parentMeta); err != nil {
+			return nil, err
+		}
+		return &ChainUpdate{Applied: []*blockchain.Block{block}}, nil
+	}
+
+	meta := newBlockMeta(block, parentMeta)
+	tipMeta, err := s.LoadBlockMeta(tip.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if !meta.betterThan(tipMeta) {
+		batch := new(leveldb.Batch)
+		if err := putBlock(batch, block); err != nil {
+			return nil, err
+		}
+		if err := putBlockMeta(batch, meta); err != nil {
+			return nil, err
+		}
+		log.Printf("Lưu block %d (%s) ở nhánh phụ", block.Header.Height, block.Hash)
+		return nil, s.db.Write(batch, nil)
+	}
+	return s.reorg(tip, tipMeta, block, meta)
+}
+
+// reorg chuyển chuỗi chính sang nhánh kết thúc tại block (chưa được lưu). Block đã có commit
+// certificate trên chuỗi chính không bao giờ bị bỏ, kể cả khi nhánh mới có certificate cao hơn.
+func (s *Storage) reorg(tip *blockchain.Block, tipMeta *BlockMeta, block *blockchain.Block, meta *BlockMeta) (*ChainUpdate, error) {
+	update := &ChainUpdate{}
+
+	// Đi lùi hai nhánh tới block chung gần nhất
+	oldBlock := tip
+	newBranch := []*blockchain.Block{block}
+	newBlock, err := s.LoadBlock(block.Header.PrevHash)
+	if err != nil {
+		return nil, err
+	}
+	for oldBlock.Hash != newBlock.Hash {
+		if oldBlock.Header.Height >= newBlock.Header.Height {
+			update.Orphaned = append(update.Orphaned, oldBlock)
+			if oldBlock, err = s.LoadBlock(oldBlock.Header.PrevHash); err != nil {
+				return nil, err
+			}
+		} else {
+			newBranch = append(newBranch, newBlock)
+			if newBlock, err = s.LoadBlock(newBlock.Header.PrevHash); err != nil {
+				return nil, err
+			}
+		}
+	}
+	ancestor := oldBlock
+	if ancestor.Header.Height < tipMeta.FinalizedHeight {
+		return nil, fmt.Errorf("%w: nhánh của block %s rẽ ra tại height %d, chuỗi chính đã finalized tới height %d",
+			ErrFinalizedReorg, block.Hash, ancestor.Header.Height, tipMeta.FinalizedHeight)
+	}
+
+	// Hoàn tác từ block cuối lùi về: trạng thái trước block thấp nhất được giữ lại sau cùng
+	changes := blockchain.StateChanges{}
+	for _, orphan := range update.Orphaned {
+		undo, err := s.loadUndo(orphan.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("không có dữ liệu hoàn tác của block %s: %w", orphan.Hash, err)
+		}
+		changes.Merge(undo)
+	}
+
+	batch := new(leveldb.Batch)
+	for _, orphan := range update.Orphaned {
+		deleteTxIndex(batch, orphan)
+	}
+	nodes := blockchain.StateNodes{}
+	parent := ancestor
+	for i := len(newBranch) - 1; i >= 0; i-- {
+		b := newBranch[i]
+		state := changes.Overlay(s)
+		blockChanges, err := blockchain.ApplyTransactions(state, b.Transactions)
+		if err != nil {
+			return nil, fmt.Errorf("block %d (%s) của nhánh mới không áp dụng được: %w", b.Header.Height, b.Hash, err)
+		}
+		blockNodes, err := blockchain.CheckStateRoot(nodes.Overlay(s), parent, b, blockChanges)
+		if err != nil {
+			return nil, fmt.Errorf("block %d (%s) của nhánh mới không áp dụng được: %w", b.Header.Height, b.Hash, err)
+		}
+		nodes.Merge(blockNodes)
+		parent = b
+		undo, err := undoChanges(state, blockChanges)
+		if err != nil {
+			return nil, err
+		}
+		if err := putUndo(batch, b.Hash, undo); err != nil {
+			return nil, err
+		}
+		changes.Merge(blockChanges)
+		if err := putTxIndex(batch, b); err != nil {
+			return nil, err
+		}
+		batch.Put(heightKey(b.Header.Height), []byte(b.Hash))
+		update.Applied = append(update.Applied, b)
+	}
+	for height := block.Header.Height + 1; height <= tip.Header.Height; height++ {
+		batch.Delete(heightKey(height))
+	}
+
+	if err := s.putStateChanges(batch, changes); err != nil {
+		return nil, err
+	}
+	putStateNodes(batch, nodes)
+	if err := putBlock(batch, block); err != nil {
+		return nil, err
+	}
+	if err := putBlockMeta(batch, meta); err != nil {
+		return nil, err
+	}
+	batch.Put([]byte("last_block_hash"), []byte(block.Hash))
+	if err := s.db.Write(batch, nil); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Đổi nhánh chính tại block %d (%s): bỏ %d block, áp dụng %d block, block cuối mới %d (%s)",
+		ancestor.Header.Height, ancestor.Hash, len(update.Orphaned), len(update.Applied), block.Header.Height, block.Hash)
+	return update, nil
+}
@@ -0,0 +1,347 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	alice = strings.Repeat("a", blockchain.AddressLength)
+	bob   = strings.Repeat("b", blockchain.AddressLength)
+	carol = strings.Repeat("c", blockchain.AddressLength)
+)
+
+type noAccounts struct{}
+
+func (noAccounts) GetAccountState(string) (blockchain.AccountState, error) {
+	return blockchain.AccountState{}, nil
+}
+
+// testBranch dựng block của một nhánh và giữ trạng thái ví cùng nút cây trạng thái sau block cuối của nhánh.
+type testBranch struct {
+	proposer string
+	tip      *blockchain.Block
+	state    blockchain.StateChanges
+	nodes    blockchain.StateNodes
+}
+
+// newChainStorage tạo kho trong thư mục tạm với genesis cấp 1000 cho alice và bob.
+func newChainStorage(t *testing.T) (*Storage, *testBranch) {
+	t.Helper()
+	genesis := (&blockchain.Genesis{
+		ChainID:   "storage-test",
+		Timestamp: 1700000000,
+		Allocations: []blockchain.GenesisAllocation{
+			{Address: alice, Balance: 1000},
+			{Address: bob, Balance: 1000},
+		},
+	}).Block()
+
+	s := NewStorage(t.TempDir())
+	t.Cleanup(s.Close)
+	if err := s.InitGenesis(genesis); err != nil {
+		t.Fatal(err)
+	}
+
+	state := blockchain.StateChanges{alice: {Balance: 1000}, bob: {Balance: 1000}}
+	_, nodes, err := blockchain.UpdateStateRoot(blockchain.StateNodes{}, blockchain.EmptyStateRoot, state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s, &testBranch{proposer: "main", tip: genesis, state: state, nodes: nodes}
+}
+
+// fork tạo nhánh mới rẽ ra từ block cuối hiện tại của b.
+func (b *testBranch) fork(proposer string) *testBranch {
+	state := blockchain.StateChanges{}
+	state.Merge(b.state)
+	nodes := blockchain.StateNodes{}
+	nodes.Merge(b.nodes)
+	return &testBranch{proposer: proposer, tip: b.tip, state: state, nodes: nodes}
+}
+
+// extend thêm vào nhánh block chứa txs với độ khó difficulty. finalized gắn commit certificate
+// cho block; storage không kiểm tra phiếu nên certificate không cần phiếu.
+func (b *testBranch) extend(t *testing.T, difficulty uint32, finalized bool, txs ...blockchain.Transaction) *blockchain.Block {
+	t.Helper()
+	changes, err := blockchain.ApplyTransactions(b.state.Overlay(noAccounts{}), txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root, nodes, err := blockchain.UpdateStateRoot(b.nodes, b.tip.Header.StateRoot, changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.nodes.Merge(nodes)
+	b.state.Merge(changes)
+
+	block := blockchain.NewBlock(b.tip, txs, root, b.tip.Header.Timestamp+1, b.proposer)
+	block.Header.Difficulty = difficulty
+	block.Hash = block.CalculateHash()
+	if finalized {
+		block.Certificate = &blockchain.CommitCertificate{Height: block.Header.Height, BlockHash: block.Hash}
+	}
+	b.tip = block
+	return block
+}
+
+func transfer(sender, receiver string, amount blockchain.Amount, nonce uint64) blockchain.Transaction {
+	return blockchain.Transaction{Sender: sender, Receiver: receiver, Amount: amount, Timestamp: 1700000000, Nonce: nonce}
+}
+
+func addBlocks(t *testing.T, s *Storage, blocks ...*blockchain.Block) {
+	t.Helper()
+	for _, block := range blocks {
+		if _, err := s.AddBlock(block); err != nil {
+			t.Fatalf("AddBlock %d (%s): %v", block.Header.Height, block.Header.Proposer, err)
+		}
+	}
+}
+
+// checkMainChain kiểm tra chuỗi chính của s trùng với nhánh b: block cuối, block theo height,
+// ví của mọi tài khoản và state proof của chúng theo state root của block cuối.
+func checkMainChain(t *testing.T, s *Storage, b *testBranch) {
+	t.Helper()
+	tip, err := s.GetLatestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tip.Hash != b.tip.Hash {
+		t.Fatalf("block cuối = %d (%s), mong đợi %d (%s)", tip.Header.Height, tip.Header.Proposer, b.tip.Header.Height, b.tip.Header.Proposer)
+	}
+	for block := b.tip; block.Header.Height > 0; {
+		stored, err := s.LoadBlockByHeight(block.Header.Height)
+		if err != nil {
+			t.Fatalf("LoadBlockByHeight(%d): %v", block.Header.Height, err)
+		}
+		if stored.Hash != block.Hash {
+			t.Errorf("block tại height %d = %s, mong đợi %s", block.Header.Height, stored.Hash, block.Hash)
+		}
+		if block, err = s.LoadBlock(block.Header.PrevHash); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.LoadBlockByHeight(b.tip.Header.Height + 1); err != leveldb.ErrNotFound {
+		t.Errorf("còn block trên chuỗi chính sau height %d (err = %v)", b.tip.Header.Height, err)
+	}
+
+	for _, address := range []string{alice, bob, carol} {
+		want := b.state[address]
+		got, err := s.GetAccountState(address)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("ví %.4s = %+v, mong đợi %+v", address, got, want)
+		}
+
+		_, proof, err := s.LoadStateProof(address, b.tip.Header.Height)
+		if err != nil {
+			t.Fatalf("LoadStateProof(%.4s): %v", address, err)
+		}
+		if err := blockchain.VerifyStateProof(proof, tip.Header.StateRoot); err != nil {
+			t.Errorf("state proof của %.4s: %v", address, err)
+		}
+		var proved blockchain.AccountState
+		if proof.Account != nil {
+			proved = *proof.Account
+		}
+		if proved != want {
+			t.Errorf("state proof của %.4s chứng minh %+v, mong đợi %+v", address, proved, want)
+		}
+	}
+}
+
+// checkTxIndex kiểm tra chỉ mục theo hash của txs (có hoặc không có) và danh sách giao dịch theo địa chỉ
+// (byAddress liệt kê cũ nhất trước, LoadAddressTxs trả về mới nhất trước).
+func checkTxIndex(t *testing.T, s *Storage, indexed, removed []blockchain.Transaction, byAddress map[string][]blockchain.Transaction) {
+	t.Helper()
+	for _, tx := range indexed {
+		if _, err := s.LoadTxLocation(tx.ID()); err != nil {
+			t.Errorf("giao dịch %.8s không có trong chỉ mục: %v", tx.ID(), err)
+		}
+	}
+	for _, tx := range removed {
+		if loc, err := s.LoadTxLocation(tx.ID()); err != leveldb.ErrNotFound {
+			t.Errorf("giao dịch %.8s của block bị bỏ vẫn trong chỉ mục: %+v, %v", tx.ID(), loc, err)
+		}
+	}
+	for address, txs := range byAddress {
+		locs, err := s.LoadAddressTxs(address, 0, 100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, loc := range locs {
+			got = append(got, loc.TxHash)
+		}
+		var want []string
+		for i := len(txs) - 1; i >= 0; i-- {
+			want = append(want, txs[i].ID())
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("giao dịch của %.4s = %v, mong đợi %v", address, got, want)
+		}
+	}
+}
+
+func TestForkChoicePrefersFinalizedBranchOverWork(t *testing.T) {
+	s, main := newChainStorage(t)
+	addBlocks(t, s, main.extend(t, 0, false, transfer(alice, carol, 100, 0)))
+	side := main.fork("side")
+	addBlocks(t, s, main.extend(t, 0, true))
+
+	// Nhánh phụ rẽ từ block 1 có work lớn hơn nhiều nhưng certificate cao nhất của nó thấp hơn chuỗi chính
+	for i := 0; i < 3; i++ {
+		block := side.extend(t, 16, false, transfer(bob, carol, 1, uint64(i)))
+		update, err := s.AddBlock(block)
+		if err != nil {
+			t.Fatalf("AddBlock nhánh phụ: %v", err)
+		}
+		if update != nil {
+			t.Fatalf("nhánh phụ có certificate thấp hơn lại thành chuỗi chính: %+v", update)
+		}
+	}
+	checkMainChain(t, s, main)
+}
+
+func TestReorgRestoresStateAndIndexes(t *testing.T) {
+	s, main := newChainStorage(t)
+	genesis, err := s.LoadBlockByHeight(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mintAlice, mintBob := genesis.Transactions[0], genesis.Transactions[1]
+	tx1 := transfer(alice, carol, 100, 0)
+	addBlocks(t, s, main.extend(t, 0, false, tx1))
+
+	side := main.fork("side")
+	mainTx2, mainTx3 := transfer(bob, carol, 50, 0), transfer(carol, alice, 30, 0)
+	addBlocks(t, s,
+		main.extend(t, 0, false, mainTx2),
+		main.extend(t, 0, false, mainTx3),
+	)
+
+	sideTx2, sideTx3 := transfer(alice, bob, 10, 1), transfer(bob, alice, 5, 0)
+	sideTx4 := transfer(alice, bob, 1, 2)
+	addBlocks(t, s,
+		side.extend(t, 0, false, sideTx2),
+		side.extend(t, 0, false, sideTx3),
+	)
+	// Hai nhánh bằng work: giữ chuỗi chính hiện tại
+	checkMainChain(t, s, main)
+
+	addBlocks(t, s, side.extend(t, 0, false, sideTx4))
+	checkMainChain(t, s, side)
+	checkTxIndex(t, s,
+		[]blockchain.Transaction{tx1, sideTx2, sideTx3, sideTx4},
+		[]blockchain.Transaction{mainTx2, mainTx3},
+		map[string][]blockchain.Transaction{
+			alice: {mintAlice, tx1, sideTx2, sideTx3, sideTx4},
+			bob:   {mintBob, sideTx2, sideTx3, sideTx4},
+			carol: {tx1},
+		},
+	)
+
+	// Đổi ngược về nhánh cũ: dữ liệu hoàn tác của các block nhánh phụ cũng phải đúng
+	mainTx4, mainTx5 := transfer(alice, carol, 7, 1), transfer(alice, bob, 3, 2)
+	addBlocks(t, s,
+		main.extend(t, 0, false, mainTx4),
+		main.extend(t, 0, false, mainTx5),
+	)
+	checkMainChain(t, s, main)
+	checkTxIndex(t, s,
+		[]blockchain.Transaction{tx1, mainTx2, mainTx3, mainTx4, mainTx5},
+		[]blockchain.Transaction{sideTx2, sideTx3, sideTx4},
+		map[string][]blockchain.Transaction{
+			alice: {mintAlice, tx1, mainTx3, mainTx4, mainTx5},
+			bob:   {mintBob, mainTx2, mainTx5},
+			carol: {tx1, mainTx2, mainTx3, mainTx4},
+		},
+	)
+}
+
+func TestReorgPastFinalizedBlockRefused(t *testing.T) {
+	tests := []struct {
+		name string
+		// side dựng nhánh rẽ từ block 1 và trả về các block của nó; block cuối gây đổi nhánh
+		side func(t *testing.T, b *testBranch) []*blockchain.Block
+	}{
+		{"certificate cùng height, nhiều work hơn", func(t *testing.T, b *testBranch) []*blockchain.Block {
+			return []*blockchain.Block{b.extend(t, 8, true)}
+		}},
+		{"certificate cao hơn", func(t *testing.T, b *testBranch) []*blockchain.Block {
+			return []*blockchain.Block{b.extend(t, 0, false), b.extend(t, 0, false), b.extend(t, 0, true)}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, main := newChainStorage(t)
+			addBlocks(t, s, main.extend(t, 0, false, transfer(alice, carol, 100, 0)))
+			side := main.fork("side")
+			addBlocks(t, s, main.extend(t, 0, true, transfer(bob, carol, 50, 0)))
+
+			blocks := tt.side(t, side)
+			addBlocks(t, s, blocks[:len(blocks)-1]...)
+			last := blocks[len(blocks)-1]
+			update, err := s.AddBlock(last)
+			if !errors.Is(err, ErrFinalizedReorg) {
+				t.Fatalf("AddBlock = %+v, %v; mong đợi %v", update, err, ErrFinalizedReorg)
+			}
+			checkMainChain(t, s, main)
+			if _, err := s.LoadBlockMeta(last.Hash); err != leveldb.ErrNotFound {
+				t.Errorf("block bị từ chối vẫn được lưu (err = %v)", err)
+			}
+		})
+	}
+}
+
+func TestChainUpdateListenersSeeReorgInOrder(t *testing.T) {
+	s, main := newChainStorage(t)
+	var updates []*ChainUpdate
+	s.OnChainUpdate(func(update *ChainUpdate) {
+		updates = append(updates, update)
+	})
+
+	b1 := main.extend(t, 0, false)
+	side := main.fork("side")
+	b2, b3 := main.extend(t, 0, false), main.extend(t, 0, false)
+	addBlocks(t, s, b1, b2, b3)
+	s2, s3 := side.extend(t, 0, false), side.extend(t, 0, false)
+	addBlocks(t, s, s2, s3)
+	s4 := side.extend(t, 0, false)
+	returned, err := s.AddBlock(s4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := func(blocks []*blockchain.Block) string {
+		var list []string
+		for _, block := range blocks {
+			list = append(list, block.Hash)
+		}
+		return strings.Join(list, ",")
+	}
+	want := []struct{ orphaned, applied []*blockchain.Block }{
+		{nil, []*blockchain.Block{b1}},
+		{nil, []*blockchain.Block{b2}},
+		{nil, []*blockchain.Block{b3}},
+		{[]*blockchain.Block{b3, b2}, []*blockchain.Block{s2, s3, s4}},
+	}
+	if len(updates) != len(want) {
+		t.Fatalf("listener nhận %d lần cập nhật, mong đợi %d", len(updates), len(want))
+	}
+	for i, w := range want {
+		if hashes(updates[i].Orphaned) != hashes(w.orphaned) || hashes(updates[i].Applied) != hashes(w.applied) {
+			t.Errorf("cập nhật #%d: bỏ %s, áp dụng %s; mong đợi bỏ %s, áp dụng %s", i,
+				hashes(updates[i].Orphaned), hashes(updates[i].Applied), hashes(w.orphaned), hashes(w.applied))
+		}
+	}
+	if returned != updates[len(updates)-1] {
+		t.Error("AddBlock trả về cập nhật khác với cập nhật gửi cho listener")
+	}
+}
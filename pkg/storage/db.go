@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 
 	"github.com/chauduongphattien/golang-chain/internal/blockchain"
 	"github.com/chauduongphattien/golang-chain/internal/network"
@@ -13,6 +14,11 @@ import (
 
 type Storage struct {
 	db *leveldb.DB
+	// mu tuần tự hoá việc áp dụng block lên trạng thái ví
+	mu sync.Mutex
+
+	listenersMu sync.Mutex
+	listeners   []func(*ChainUpdate)
 }
 
 func NewStorage(path string) *Storage {
@@ -35,16 +41,23 @@ func (s *Storage) Close() {
 	s.db.Close()
 }
 
+// SaveBlock lưu block (có thể thuộc nhánh phụ) và chọn lại chuỗi chính theo fork choice, xem AddBlock.
 func (s *Storage) SaveBlock(block *blockchain.Block) error {
-	data, err := json.Marshal(block)
+	_, err := s.AddBlock(block)
+	return err
+}
+
+func heightKey(height uint64) []byte {
+	return []byte(fmt.Sprintf("height_%020d", height))
+}
+
+// LoadBlockByHeight tải block trên chuỗi chính theo height.
+func (s *Storage) LoadBlockByHeight(height uint64) (*blockchain.Block, error) {
+	hash, err := s.db.Get(heightKey(height), nil)
 	if err != nil {
-		return err
-	}
-	blockKey := "block_" + block.Hash
-	if err := s.db.Put([]byte(blockKey), data, nil); err != nil {
-		return err
+		return nil, err
 	}
-	return s.db.Put([]byte("last_block_hash"), []byte(block.Hash), nil)
+	return s.LoadBlock(string(hash))
 }
 
 func (s *Storage) LoadBlock(hash string) (*blockchain.Block, error) {
@@ -61,17 +74,32 @@ func (s *Storage) LoadBlock(hash string) (*blockchain.Block, error) {
 }
 
 // vi
-func (s *Storage) SaveWallet(address string, wallet *network.Wallet) error {
-	data, err := json.Marshal(wallet)
+// BindPublicKey gắn public key cho ví tại địa chỉ tương ứng (tạo ví số dư 0 nếu chưa có) và trả về ví sau khi gắn.
+// Giữ mu để không ghi đè số dư và nonce do block được áp dụng cùng lúc.
+func (s *Storage) BindPublicKey(pubKey []byte) (*network.Wallet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	address := network.GetAddressFromPubKey(pubKey)
+	w, err := s.LoadWallet(address)
+	if err == leveldb.ErrNotFound {
+		w = &network.Wallet{Address: address}
+	} else if err != nil {
+		return nil, err
+	}
+	w.PublicKey = pubKey
+
+	data, err := json.Marshal(w)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err := s.db.Put([]byte("wallet:"+address), data, nil); err != nil {
+		return nil, err
 	}
-	fmt.Println(">> Lưu ví:", address)
-	return s.db.Put([]byte("wallet:"+address), data, nil)
+	return w, nil
 }
 
 func (s *Storage) LoadWallet(address string) (*network.Wallet, error) {
-	fmt.Println(">> Truy vấn ví:", address)
 	data, err := s.db.Get([]byte("wallet:"+address), nil)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const electionStateKey = "election_state"
+
+type electionState struct {
+	Term     uint64 `json:"term"`
+	VotedFor string `json:"voted_for"`
+}
+
+// LoadElectionState trả về nhiệm kỳ hiện tại và ứng viên đã được bầu trong nhiệm kỳ đó.
+func (s *Storage) LoadElectionState() (uint64, string, error) {
+	data, err := s.db.Get([]byte(electionStateKey), nil)
+	if err == leveldb.ErrNotFound {
+		return 0, "", nil
+	}
+	if err != nil {
+		return 0, "", err
+	}
+	var state electionState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return 0, "", err
+	}
+	return state.Term, state.VotedFor, nil
+}
+
+// SaveElectionState lưu nhiệm kỳ và phiếu bầu để node không bầu hai lần trong một nhiệm kỳ sau khi khởi động lại.
+func (s *Storage) SaveElectionState(term uint64, votedFor string) error {
+	data, err := json.Marshal(electionState{Term: term, VotedFor: votedFor})
+	if err != nil {
+		return err
+	}
+	return s.db.Put([]byte(electionStateKey), data, nil)
+}
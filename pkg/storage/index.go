@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// TxLocation là vị trí của giao dịch trên chuỗi chính.
+type TxLocation struct {
+	TxHash    string `json:"tx_hash"`
+	BlockHash string `json:"block_hash"`
+	Height    uint64 `json:"height"`
+	Index     int    `json:"index"` // thứ tự trong block, bắt đầu từ 0
+}
+
+func txKey(id string) []byte {
+	return []byte("tx_" + id)
+}
+
+// addressTxPrefix được sắp theo height rồi thứ tự trong block nên duyệt theo key là duyệt theo thời gian.
+// Địa chỉ được ghi kèm độ dài để prefix của một địa chỉ không bao giờ là prefix key của địa chỉ khác.
+func addressTxPrefix(address string) []byte {
+	return []byte(fmt.Sprintf("addrtx_%d:%s_", len(address), address))
+}
+
+func addressTxKey(address string, height uint64, index int) []byte {
+	return []byte(fmt.Sprintf("addrtx_%d:%s_%020d_%06d", len(address), address, height, index))
+}
+
+// txAddresses trả về các địa chỉ liên quan tới giao dịch (người gửi và người nhận, không lặp).
+func txAddresses(tx *blockchain.Transaction) []string {
+	if tx.Sender == "" || tx.Sender == tx.Receiver {
+		return []string{tx.Receiver}
+	}
+	return []string{tx.Sender, tx.Receiver}
+}
+
+// putTxIndex ghi chỉ mục giao dịch theo hash và theo địa chỉ cho block vừa vào chuỗi chính.
+func putTxIndex(batch *leveldb.Batch, block *blockchain.Block) error {
+	for i := range block.Transactions {
+		tx := &block.Transactions[i]
+		loc := TxLocation{TxHash: tx.ID(), BlockHash: block.Hash, Height: block.Header.Height, Index: i}
+		data, err := json.Marshal(loc)
+		if err != nil {
+			return err
+		}
+		batch.Put(txKey(loc.TxHash), data)
+		for _, address := range txAddresses(tx) {
+			batch.Put(addressTxKey(address, loc.Height, i), data)
+		}
+	}
+	return nil
+}
+
+// deleteTxIndex xoá chỉ mục của block bị bỏ khỏi chuỗi chính khi đổi nhánh.
+func deleteTxIndex(batch *leveldb.Batch, block *blockchain.Block) {
+	for i := range block.Transactions {
+		tx := &block.Transactions[i]
+		batch.Delete(txKey(tx.ID()))
+		for _, address := range txAddresses(tx) {
+			batch.Delete(addressTxKey(address, block.Header.Height, i))
+		}
+	}
+}
+
+// LoadTxLocation trả về vị trí của giao dịch trên chuỗi chính (leveldb.ErrNotFound nếu không có).
+func (s *Storage) LoadTxLocation(id string) (*TxLocation, error) {
+	data, err := s.db.Get(txKey(id), nil)
+	if err != nil {
+		return nil, err
+	}
+	var loc TxLocation
+	if err := json.Unmarshal(data, &loc); err != nil {
+		return nil, err
+	}
+	return &loc, nil
+}
+
+// LoadAddressTxs trả về giao dịch trên chuỗi chính có address là người gửi hoặc người nhận,
+// mới nhất trước, bỏ qua offset giao dịch đầu và lấy tối đa limit giao dịch.
+func (s *Storage) LoadAddressTxs(address string, offset, limit int) ([]TxLocation, error) {
+	iter := s.db.NewIterator(util.BytesPrefix(addressTxPrefix(address)), nil)
+	defer iter.Release()
+
+	var locs []TxLocation
+	for ok := iter.Last(); ok && len(locs) < limit; ok = iter.Prev() {
+		if offset > 0 {
+			offset--
+			continue
+		}
+		var loc TxLocation
+		if err := json.Unmarshal(iter.Value(), &loc); err != nil {
+			return nil, err
+		}
+		locs = append(locs, loc)
+	}
+	return locs, iter.Error()
+}
+
+// LoadTxProof trả về block trên chuỗi chính chứa giao dịch cùng Merkle proof của giao dịch trong block.
+func (s *Storage) LoadTxProof(id string) (*blockchain.Block, *blockchain.MerkleProof, error) {
+	loc, err := s.LoadTxLocation(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	block, err := s.LoadBlock(loc.BlockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := blockchain.BuildMerkleProof(block.Transactions, loc.Index)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, proof, nil
+}
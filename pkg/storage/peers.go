@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/chauduongphattien/golang-chain/internal/p2p/peers"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const peerKeyPrefix = "peer_"
+
+func peerKey(address string) []byte {
+	return []byte(peerKeyPrefix + address)
+}
+
+// LoadPeers trả về các peer đã lưu bởi SavePeers.
+func (s *Storage) LoadPeers() ([]peers.Peer, error) {
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(peerKeyPrefix)), nil)
+	defer iter.Release()
+
+	var list []peers.Peer
+	for iter.Next() {
+		var p peers.Peer
+		if err := json.Unmarshal(iter.Value(), &p); err != nil {
+			return nil, err
+		}
+		if p.Address == "" {
+			p.Address = strings.TrimPrefix(string(iter.Key()), peerKeyPrefix)
+		}
+		list = append(list, p)
+	}
+	return list, iter.Error()
+}
+
+// SavePeers ghi danh sách peer trong một batch.
+func (s *Storage) SavePeers(list []peers.Peer) error {
+	batch := new(leveldb.Batch)
+	for _, p := range list {
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		batch.Put(peerKey(p.Address), data)
+	}
+	return s.db.Write(batch, nil)
+}
+
+func (s *Storage) DeletePeer(address string) error {
+	return s.db.Delete(peerKey(address), nil)
+}
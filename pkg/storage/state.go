@@ -0,0 +1,203 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/chauduongphattien/golang-chain/internal/network"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// GetAccountState trả về số dư hiện tại của địa chỉ; ví chưa tồn tại được coi là số dư 0.
+func (s *Storage) GetAccountState(address string) (blockchain.AccountState, error) {
+	w, err := s.LoadWallet(address)
+	if err == leveldb.ErrNotFound {
+		return blockchain.AccountState{}, nil
+	}
+	if err != nil {
+		return blockchain.AccountState{}, err
+	}
+	return blockchain.AccountState{Balance: w.Token, Nonce: w.Nonce}, nil
+}
+
+// ApplyBlock áp dụng toàn bộ giao dịch của block lên các ví và lưu block
+// trong cùng một batch LevelDB, nên hoặc tất cả được ghi hoặc không gì cả.
+// Block phải nối tiếp block cuối; block thuộc nhánh khác dùng AddBlock.
+func (s *Storage) ApplyBlock(block *blockchain.Block) error {
+	s.mu.Lock()
+	err := s.applyTip(block)
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+	s.notify(&ChainUpdate{Applied: []*blockchain.Block{block}})
+	return nil
+}
+
+func (s *Storage) applyTip(block *blockchain.Block) error {
+	if err := s.checkParent(block); err != nil {
+		return err
+	}
+	parentMeta, err := s.LoadBlockMeta(block.Header.PrevHash)
+	if err != nil {
+		return fmt.Errorf("không tải được meta của block cha: %w", err)
+	}
+	return s.applyBlock(block, parentMeta)
+}
+
+// applyBlock ghi block nối tiếp block cuối cùng trạng thái ví, dữ liệu hoàn tác và meta.
+func (s *Storage) applyBlock(block *blockchain.Block, parentMeta *BlockMeta) error {
+	changes, err := blockchain.ApplyTransactions(s, block.Transactions)
+	if err != nil {
+		return err
+	}
+	parent, err := s.LoadBlock(block.Header.PrevHash)
+	if err != nil {
+		return fmt.Errorf("không tải được block cha: %w", err)
+	}
+	nodes, err := blockchain.CheckStateRoot(s, parent, block, changes)
+	if err != nil {
+		return err
+	}
+	undo, err := undoChanges(s, changes)
+	if err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	if err := s.putStateChanges(batch, changes); err != nil {
+		return err
+	}
+	putStateNodes(batch, nodes)
+	if err := putUndo(batch, block.Hash, undo); err != nil {
+		return err
+	}
+	if err := putCanonicalBlock(batch, block); err != nil {
+		return err
+	}
+	if err := putBlockMeta(batch, newBlockMeta(block, parentMeta)); err != nil {
+		return err
+	}
+
+	return s.db.Write(batch, nil)
+}
+
+// InitGenesis ghi genesis block, số dư ban đầu và cây trạng thái của chúng nếu chuỗi còn rỗng.
+// Nếu chuỗi đã có dữ liệu thì genesis đã lưu phải trùng hash với genesis được cấu hình.
+func (s *Storage) InitGenesis(genesis *blockchain.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, err := s.GetGenesisHash()
+	if err == nil {
+		if stored != genesis.Hash {
+			return fmt.Errorf("genesis trong dữ liệu (%s) khác genesis cấu hình (%s)", stored, genesis.Hash)
+		}
+		return nil
+	}
+	if err != leveldb.ErrNotFound {
+		return err
+	}
+	if _, err := s.GetLatestBlock(); err != leveldb.ErrNotFound {
+		return fmt.Errorf("dữ liệu đã có block nhưng thiếu genesis, cần xoá dữ liệu cũ")
+	}
+
+	changes, err := blockchain.ApplyGenesisTransactions(s, genesis.Transactions)
+	if err != nil {
+		return err
+	}
+	root, nodes, err := blockchain.UpdateStateRoot(s, blockchain.EmptyStateRoot, changes)
+	if err != nil {
+		return err
+	}
+	if root != genesis.Header.StateRoot {
+		return fmt.Errorf("state root của genesis (%s) khác số dư ban đầu (%s)", genesis.Header.StateRoot, root)
+	}
+
+	batch := new(leveldb.Batch)
+	if err := s.putStateChanges(batch, changes); err != nil {
+		return err
+	}
+	putStateNodes(batch, nodes)
+	if err := putCanonicalBlock(batch, genesis); err != nil {
+		return err
+	}
+	if err := putBlockMeta(batch, newBlockMeta(genesis, nil)); err != nil {
+		return err
+	}
+	batch.Put([]byte("genesis_hash"), []byte(genesis.Hash))
+
+	return s.db.Write(batch, nil)
+}
+
+func (s *Storage) GetGenesisHash() (string, error) {
+	hash, err := s.db.Get([]byte("genesis_hash"), nil)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (s *Storage) putStateChanges(batch *leveldb.Batch, changes blockchain.StateChanges) error {
+	for address, acc := range changes {
+		w, err := s.LoadWallet(address)
+		if err == leveldb.ErrNotFound {
+			w = &network.Wallet{Address: address}
+		} else if err != nil {
+			return err
+		}
+		w.Token = acc.Balance
+		w.Nonce = acc.Nonce
+
+		data, err := json.Marshal(w)
+		if err != nil {
+			return err
+		}
+		batch.Put([]byte("wallet:"+address), data)
+	}
+	return nil
+}
+
+// putBlock ghi nội dung block mà không đổi chuỗi chính.
+func putBlock(batch *leveldb.Batch, block *blockchain.Block) error {
+	data, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	batch.Put([]byte("block_"+block.Hash), data)
+	return nil
+}
+
+// putCanonicalBlock ghi block, chỉ mục height và giao dịch, và đặt block làm block cuối của chuỗi.
+func putCanonicalBlock(batch *leveldb.Batch, block *blockchain.Block) error {
+	if err := putBlock(batch, block); err != nil {
+		return err
+	}
+	if err := putTxIndex(batch, block); err != nil {
+		return err
+	}
+	batch.Put(heightKey(block.Header.Height), []byte(block.Hash))
+	batch.Put([]byte("last_block_hash"), []byte(block.Hash))
+	return nil
+}
+
+// checkParent đảm bảo block nối tiếp đúng block cuối hiện tại (height = parent + 1).
+func (s *Storage) checkParent(block *blockchain.Block) error {
+	parent, err := s.GetLatestBlock()
+	if err == leveldb.ErrNotFound {
+		return fmt.Errorf("chuỗi chưa có genesis block")
+	}
+	if err != nil {
+		return err
+	}
+
+	if block.Header.PrevHash != parent.Hash {
+		return fmt.Errorf("block %s không nối tiếp block cuối %s", block.Hash, parent.Hash)
+	}
+	if block.Header.Height != parent.Header.Height+1 {
+		return fmt.Errorf("height không hợp lệ: mong đợi %d, nhận %d", parent.Header.Height+1, block.Header.Height)
+	}
+	return nil
+}
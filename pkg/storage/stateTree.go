@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// Nút của cây trạng thái được lưu theo hash và không bao giờ bị xoá, nên có thể tạo state proof
+// tại mọi block trên chuỗi chính.
+func stateNodeKey(hash []byte) []byte {
+	return append([]byte("smt_"), hash...)
+}
+
+func (s *Storage) GetStateNode(hash []byte) ([]byte, error) {
+	data, err := s.db.Get(stateNodeKey(hash), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, blockchain.ErrStateNodeNotFound
+	}
+	return data, err
+}
+
+func putStateNodes(batch *leveldb.Batch, nodes blockchain.StateNodes) {
+	for hash, data := range nodes {
+		batch.Put(stateNodeKey([]byte(hash)), data)
+	}
+}
+
+// NextStateRoot tính state root sau khi áp dụng txs lên parent, block cuối hiện tại.
+func (s *Storage) NextStateRoot(parent *blockchain.Block, txs []blockchain.Transaction) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tip, err := s.GetLatestBlock()
+	if err != nil {
+		return "", err
+	}
+	if tip.Hash != parent.Hash {
+		return "", fmt.Errorf("block cuối đã đổi từ %s sang %s", parent.Hash, tip.Hash)
+	}
+	changes, err := blockchain.ApplyTransactions(s, txs)
+	if err != nil {
+		return "", err
+	}
+	root, _, err := blockchain.UpdateStateRoot(s, parent.Header.StateRoot, changes)
+	return root, err
+}
+
+// LoadStateProof trả về block trên chuỗi chính tại height cùng state proof của address theo StateRoot của block đó.
+func (s *Storage) LoadStateProof(address string, height uint64) (*blockchain.Block, *blockchain.StateProof, error) {
+	block, err := s.LoadBlockByHeight(height)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := blockchain.BuildStateProof(s, block.Header.StateRoot, address)
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, proof, nil
+}
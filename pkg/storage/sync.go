@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const syncProgressKey = "sync_progress"
+
+// locatorDenseBlocks là số block gần nhất được đưa hết vào locator trước khi giãn khoảng cách.
+const locatorDenseBlocks = 10
+
+// BlockLocator trả về hash các block trên chuỗi chính từ block cuối lùi về: locatorDenseBlocks block
+// gần nhất, sau đó khoảng cách tăng gấp đôi mỗi bước, luôn kết thúc bằng genesis.
+func (s *Storage) BlockLocator() ([]string, error) {
+	tip, err := s.GetLatestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []string
+	height := tip.Header.Height
+	step := uint64(1)
+	for {
+		block, err := s.LoadBlockByHeight(height)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, block.Hash)
+		if height == 0 {
+			return hashes, nil
+		}
+		if len(hashes) >= locatorDenseBlocks {
+			step *= 2
+		}
+		if height < step {
+			height = 0
+		} else {
+			height -= step
+		}
+	}
+}
+
+// SyncProgress là tiến độ đồng bộ gần nhất từ một peer; được lưu sau mỗi lô block để lần đồng bộ
+// sau tiếp tục từ LastHash thay vì tải lại từ block chung.
+type SyncProgress struct {
+	Peer           string    `json:"peer"`
+	TargetHeight   uint64    `json:"target_height"`
+	TargetHash     string    `json:"target_hash"`
+	AncestorHeight uint64    `json:"ancestor_height"`
+	HeaderHeight   uint64    `json:"header_height"` // header cao nhất đã tải và kiểm tra
+	LastHeight     uint64    `json:"last_height"`   // block cao nhất đã lưu
+	LastHash       string    `json:"last_hash"`
+	Done           bool      `json:"done"`
+	Error          string    `json:"error,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// LoadSyncProgress trả về nil nếu node chưa từng đồng bộ.
+func (s *Storage) LoadSyncProgress() (*SyncProgress, error) {
+	data, err := s.db.Get([]byte(syncProgressKey), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var progress SyncProgress
+	if err := json.Unmarshal(data, &progress); err != nil {
+		return nil, err
+	}
+	return &progress, nil
+}
+
+func (s *Storage) SaveSyncProgress(progress *SyncProgress) error {
+	data, err := json.Marshal(progress)
+	if err != nil {
+		return err
+	}
+	return s.db.Put([]byte(syncProgressKey), data, nil)
+}
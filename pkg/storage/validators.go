@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const validatorSetPrefix = "validators_"
+
+func validatorSetKey(height uint64) []byte {
+	return []byte(fmt.Sprintf("%s%020d", validatorSetPrefix, height))
+}
+
+// SaveValidatorSet lưu tập validator có hiệu lực từ set.Height. Tập đã lưu ở cùng height bị ghi đè.
+func (s *Storage) SaveValidatorSet(set *consensus.ValidatorSet) error {
+	if err := set.Validate(); err != nil {
+		return err
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(validatorSetKey(set.Height), data, nil)
+}
+
+// ValidatorSetAt trả về tập validator có hiệu lực tại height, tức tập có Height lớn nhất <= height.
+func (s *Storage) ValidatorSetAt(height uint64) (*consensus.ValidatorSet, error) {
+	limit := validatorSetKey(height + 1)
+	if height == math.MaxUint64 {
+		// height + 1 tràn về 0
+		limit = util.BytesPrefix([]byte(validatorSetPrefix)).Limit
+	}
+	iter := s.db.NewIterator(&util.Range{Start: []byte(validatorSetPrefix), Limit: limit}, nil)
+	defer iter.Release()
+
+	if !iter.Last() {
+		if err := iter.Error(); err != nil {
+			return nil, err
+		}
+		return nil, leveldb.ErrNotFound
+	}
+
+	var set consensus.ValidatorSet
+	if err := json.Unmarshal(iter.Value(), &set); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// InitValidatorSets lưu các tập validator của genesis (tập ban đầu và validator_updates), mỗi tập theo
+// height kích hoạt của nó. Trả lỗi nếu dữ liệu đã có tập khác genesis (khác validator, public key, voting power
+// hay quorum, hoặc có tập ở height genesis không có); tập chỉ khác địa chỉ validator được ghi đè.
+func (s *Storage) InitValidatorSets(sets consensus.ValidatorSets) error {
+	byHeight := make(map[uint64]*consensus.ValidatorSet, len(sets))
+	for _, set := range sets {
+		byHeight[set.Height] = set
+	}
+
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(validatorSetPrefix)), nil)
+	for iter.Next() {
+		var stored consensus.ValidatorSet
+		if err := json.Unmarshal(iter.Value(), &stored); err != nil {
+			iter.Release()
+			return err
+		}
+		set, ok := byHeight[stored.Height]
+		if !ok || !set.SameVoters(&stored) {
+			iter.Release()
+			return fmt.Errorf("tập validator đã lưu tại height %d khác genesis, cần xoá dữ liệu cũ", stored.Height)
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	for _, set := range sets {
+		if err := s.SaveValidatorSet(set); err != nil {
+			return err
+		}
+	}
+	return nil
+}
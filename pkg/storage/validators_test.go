@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"math"
+	"testing"
+
+	"github.com/chauduongphattien/golang-chain/internal/consensus"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// testValidatorSet tạo tập một validator có voting power bằng height kích hoạt + 1 để phân biệt các tập.
+func testValidatorSet(t *testing.T, height uint64) *consensus.ValidatorSet {
+	t.Helper()
+	set, err := consensus.NewValidatorSet(height, []consensus.Validator{
+		{ID: "v1", PublicKey: "0a0b", Power: height + 1},
+	}, consensus.DefaultQuorum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return set
+}
+
+func TestValidatorSetAtActivationHeights(t *testing.T) {
+	s := NewStorage(t.TempDir())
+	t.Cleanup(s.Close)
+
+	if _, err := s.ValidatorSetAt(0); err != leveldb.ErrNotFound {
+		t.Fatalf("ValidatorSetAt khi chưa có tập nào = %v, mong đợi %v", err, leveldb.ErrNotFound)
+	}
+
+	// Hai tập kích hoạt ở hai height liên tiếp, một tập ở height rất lớn
+	activations := []uint64{0, 10, 11, 1000, math.MaxUint64 - 1}
+	var sets consensus.ValidatorSets
+	for _, height := range activations {
+		sets = append(sets, testValidatorSet(t, height))
+	}
+	if err := s.InitValidatorSets(sets); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		height uint64
+		want   uint64 // height kích hoạt của tập có hiệu lực
+	}{
+		{0, 0},
+		{1, 0},
+		{9, 0},
+		{10, 10},
+		{11, 11},
+		{12, 11},
+		{999, 11},
+		{1000, 1000},
+		{1001, 1000},
+		{math.MaxUint64 - 2, 1000},
+		{math.MaxUint64 - 1, math.MaxUint64 - 1},
+		{math.MaxUint64, math.MaxUint64 - 1},
+	}
+	for _, tt := range tests {
+		set, err := s.ValidatorSetAt(tt.height)
+		if err != nil {
+			t.Errorf("ValidatorSetAt(%d): %v", tt.height, err)
+			continue
+		}
+		if set.Height != tt.want || set.Validators[0].Power != tt.want+1 {
+			t.Errorf("ValidatorSetAt(%d) trả về tập kích hoạt tại %d, mong đợi %d", tt.height, set.Height, tt.want)
+		}
+		// Bản trong bộ nhớ của genesis phải cho cùng kết quả
+		mem, err := sets.ValidatorSetAt(tt.height)
+		if err != nil || !mem.SameVoters(set) {
+			t.Errorf("ValidatorSets.ValidatorSetAt(%d) khác storage: %+v, %v", tt.height, mem, err)
+		}
+	}
+}
+
+func TestValidatorSetAtWithoutGenesisSet(t *testing.T) {
+	s := NewStorage(t.TempDir())
+	t.Cleanup(s.Close)
+	if err := s.SaveValidatorSet(testValidatorSet(t, 5)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ValidatorSetAt(4); err != leveldb.ErrNotFound {
+		t.Errorf("ValidatorSetAt trước tập đầu tiên = %v, mong đợi %v", err, leveldb.ErrNotFound)
+	}
+	if set, err := s.ValidatorSetAt(5); err != nil || set.Height != 5 {
+		t.Errorf("ValidatorSetAt(5) = %+v, %v", set, err)
+	}
+}
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/chauduongphattien/golang-chain/internal/blockchain"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+const lastVoteKey = "last_vote"
+
+// LoadLastVote trả về phiếu gần nhất node đã ký, false nếu node chưa ký phiếu nào.
+func (s *Storage) LoadLastVote() (blockchain.Vote, bool, error) {
+	data, err := s.db.Get([]byte(lastVoteKey), nil)
+	if err == leveldb.ErrNotFound {
+		return blockchain.Vote{}, false, nil
+	}
+	if err != nil {
+		return blockchain.Vote{}, false, err
+	}
+	var vote blockchain.Vote
+	if err := json.Unmarshal(data, &vote); err != nil {
+		return blockchain.Vote{}, false, err
+	}
+	return vote, true, nil
+}
+
+// SaveLastVote lưu phiếu vừa ký để sau khi khởi động lại node vẫn không ký phiếu mâu thuẫn với nó.
+// Ghi đồng bộ xuống đĩa vì phiếu chỉ được gửi đi sau khi đã lưu.
+func (s *Storage) SaveLastVote(vote blockchain.Vote) error {
+	data, err := json.Marshal(vote)
+	if err != nil {
+		return err
+	}
+	return s.db.Put([]byte(lastVoteKey), data, &opt.WriteOptions{Sync: true})
+}